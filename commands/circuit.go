@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CircuitInfo performs the actions for the "circuit <circuitID>" command sent to the bot,
+// which shows information about a circuit and the grand prix held there.
+func CircuitInfo(ctx context.Context, data DataSource, args ...string) (*discordgo.MessageSend, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("command 'circuit' needs a circuitID as an argument")
+	}
+
+	circuitID := args[0]
+
+	// Get circuits
+	circuitTable, err := data.Circuits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of circuits from ergast: %w", err)
+	}
+
+	circuit, ok := circuitTable.FindCircuit(circuitID)
+	if !ok {
+		return &discordgo.MessageSend{Content: suggestID("circuit", circuitID, circuitTable.CircuitIDs())}, nil
+	}
+
+	// Get the winners of all races at the circuit from the API
+	raceTable, err := data.RequestCircuitResults(ctx, circuitID)
+	if err != nil {
+		return nil, fmt.Errorf("requesting circuit results to ergast: %w", err)
+	}
+
+	races := raceTable.Races
+	first := races[0]
+	latest := races[len(races)-1]
+
+	driverWins := make(map[string]int)
+	driverNames := make(map[string]string)
+	constructorWins := make(map[string]int)
+	constructorNames := make(map[string]string)
+
+	for _, race := range races {
+		if len(race.Results) == 0 {
+			continue
+		}
+		winner := race.Results[0]
+		driverWins[winner.Driver.DriverID]++
+		driverNames[winner.Driver.DriverID] = winner.Driver.FullName()
+		constructorWins[winner.Constructor.ConstructorID]++
+		constructorNames[winner.Constructor.ConstructorID] = winner.Constructor.Name
+	}
+
+	bestDriver, bestDriverWins := mostWins(driverWins)
+	bestConstructor, bestConstructorWins := mostWins(constructorWins)
+
+	// Build message
+	var message discordgo.MessageSend
+	var circuitEmbed discordgo.MessageEmbed
+	var historyEmbed discordgo.MessageEmbed
+
+	message.Embeds = append(message.Embeds, &circuitEmbed)
+	message.Embeds = append(message.Embeds, &historyEmbed)
+
+	circuitEmbed.Title = circuit.CircuitName
+	circuitEmbed.URL = circuit.URL
+
+	circuitEmbed.Fields = append(circuitEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Location",
+		Value:  fmt.Sprintf("%s (%s)", circuit.Location.Locality, circuit.Location.Country),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Coordinates",
+		Value:  fmt.Sprintf("%s, %s", circuit.Location.Lat, circuit.Location.Long),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Wikipedia",
+		Value:  circuit.URL,
+		Inline: false,
+	})
+
+	historyEmbed.Title = "History"
+
+	historyEmbed.Fields = append(historyEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Races held",
+		Value:  strconv.Itoa(len(races)),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "First Grand Prix",
+		Value:  fmt.Sprintf("%s %s", first.Season, first.RaceName),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Latest Grand Prix",
+		Value:  fmt.Sprintf("%s %s", latest.Season, latest.RaceName),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Most successful driver",
+		Value:  fmt.Sprintf("%s (%d wins)", driverNames[bestDriver], bestDriverWins),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Most successful constructor",
+		Value:  fmt.Sprintf("%s (%d wins)", constructorNames[bestConstructor], bestConstructorWins),
+		Inline: true,
+	})
+
+	return &message, nil
+}
+
+// mostWins returns the id with the most wins in a map of wins per id.
+// Ties are broken by id, so the result is always the same for the same wins.
+func mostWins(wins map[string]int) (string, int) {
+	var bestID string
+	var best int
+
+	for id, w := range wins {
+		if w > best || (w == best && id < bestID) {
+			bestID = id
+			best = w
+		}
+	}
+
+	return bestID, best
+}
@@ -0,0 +1,570 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"f1-discord-bot/ergast"
+	"f1-discord-bot/ergasttest"
+)
+
+// newServer starts a fake API serving the fixtures of the module
+func newServer(t *testing.T) *ergasttest.Server {
+	server := ergasttest.NewServer(ergasttest.Fixtures())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// checkContains checks a message contains all the given texts
+func checkContains(t *testing.T, message string, texts ...string) {
+	t.Helper()
+	for _, text := range texts {
+		if !strings.Contains(message, text) {
+			t.Errorf("message doesn't contain %q:\n%s", text, message)
+		}
+	}
+}
+
+// checkLength checks a message fits in a discord message
+func checkLength(t *testing.T, message string) {
+	t.Helper()
+	if n := utf8.RuneCountInString(message); n > MaxMessageLength {
+		t.Errorf("message has %d characters, more than the %d discord accepts", n, MaxMessageLength)
+	}
+}
+
+// checkNotRequested checks none of the requests made to a server contains the given text
+func checkNotRequested(t *testing.T, server *ergasttest.Server, text string) {
+	t.Helper()
+	for _, request := range server.Requests() {
+		if strings.Contains(request, text) {
+			t.Errorf("requested %s, which shouldn't be requested", request)
+		}
+	}
+}
+
+func TestRaceResults(t *testing.T) {
+	for _, roundOrCircuit := range []string{"1", "bahrain"} {
+		t.Run(roundOrCircuit, func(t *testing.T) {
+			server := newServer(t)
+
+			message, err := RaceResults(context.Background(), server.Client(), "2023", roundOrCircuit)
+			if err != nil {
+				t.Fatalf("RaceResults: %v", err)
+			}
+
+			checkContains(t, message, "**2023 BAHRAIN GRAND PRIX RESULTS**", "Max Verstappen", "Oscar Piastri")
+			checkLength(t, message)
+		})
+	}
+}
+
+func TestRaceResultsOfMissingRaces(t *testing.T) {
+	tests := []struct {
+		season, roundOrCircuit string
+	}{
+		{"2023", "30"},
+		{"2023", "monza"},
+		{"1900", "1"},
+		{"2023", "1/../../drivers"},
+	}
+
+	for _, tt := range tests {
+		server := newServer(t)
+
+		message, err := RaceResults(context.Background(), server.Client(), tt.season, tt.roundOrCircuit)
+		if err != nil {
+			t.Fatalf("RaceResults(%s, %s): %v", tt.season, tt.roundOrCircuit, err)
+		}
+
+		checkContains(t, message, "**UPS!**")
+		checkNotRequested(t, server, "results.json")
+	}
+}
+
+func TestRaceResultsOfLargeGrid(t *testing.T) {
+	server := newServer(t)
+
+	// Races of the 80s had up to 26 starters
+	race := ergast.Race{Season: "2023", Round: "1", RaceName: "Bahrain Grand Prix"}
+	for i := 1; i <= 26; i++ {
+		race.Results = append(race.Results, ergast.RaceResult{
+			Position:     strconv.Itoa(i),
+			PositionText: strconv.Itoa(i),
+			Points:       "0",
+			Driver:       ergast.Driver{GivenName: "Driver", FamilyName: fmt.Sprintf("Number %d", i)},
+			Constructor:  ergast.Constructor{Name: "Some Constructor Racing"},
+			Grid:         strconv.Itoa(i),
+			Laps:         "61",
+			Status:       "Finished",
+			Time:         ergast.MillisTime{Millis: "6000000", Time: "+1:23.456"},
+		})
+	}
+	server.SetReply("/2023/1/results.json", ergast.MRReply{MRData: ergast.MRData{
+		Total:     "26",
+		RaceTable: ergast.RaceTable{Races: []ergast.Race{race}},
+	}})
+
+	message, err := RaceResults(context.Background(), server.Client(), "2023", "1")
+	if err != nil {
+		t.Fatalf("RaceResults: %v", err)
+	}
+
+	// The whole classification is sent, split in several messages
+	for i := 1; i <= 26; i++ {
+		checkContains(t, message, fmt.Sprintf("Driver Number %d ", i))
+	}
+	messages := SplitMessage(message, MaxMessageLength)
+	if len(messages) < 2 {
+		t.Errorf("classification of %d characters was sent in %d message", utf8.RuneCountInString(message), len(messages))
+	}
+	checkMessages(t, messages, MaxMessageLength)
+}
+
+func TestQualifying(t *testing.T) {
+	server := newServer(t)
+	result := func(position, driverID, q1, q2, q3 string) ergast.QualifyingResult {
+		return ergast.QualifyingResult{Position: position, Driver: ergast.Driver{DriverID: driverID, FamilyName: driverID}, Q1: q1, Q2: q2, Q3: q3}
+	}
+	server.SetReply("/2023/1/qualifying.json", ergast.MRReply{MRData: ergast.MRData{
+		Total: "4",
+		RaceTable: ergast.RaceTable{Races: []ergast.Race{{
+			Season:   "2023",
+			RaceName: "Bahrain Grand Prix",
+			QualifyingResults: []ergast.QualifyingResult{
+				result("1", "verstappen", "1:31.295", "1:30.503", "1:29.708"),
+				result("2", "perez", "1:31.479", "1:30.746", "1:29.846"),
+				result("3", "sargeant", "1:32.000", "", ""),
+				result("4", "stroll", "", "", ""),
+			},
+		}}},
+	}})
+
+	message, err := Qualifying(context.Background(), server.Client(), "2023", "1")
+	if err != nil {
+		t.Fatalf("Qualifying: %v", err)
+	}
+
+	// Gaps are taken from the best time of each driver in any session
+	checkContains(t, message, "+0.138", "+2.292")
+	for _, line := range strings.Split(message, "\n") {
+		if strings.Contains(line, "stroll") && strings.Contains(line, "+") {
+			t.Errorf("driver without times has a gap: %s", line)
+		}
+	}
+}
+
+func TestQualifyingOfMissingRound(t *testing.T) {
+	server := newServer(t)
+	server.SetReply("/2023/30/qualifying.json", ergast.MRReply{MRData: ergast.MRData{RaceTable: ergast.RaceTable{Season: "2023", Round: "30"}}})
+
+	message, err := Qualifying(context.Background(), server.Client(), "2023", "30")
+	if err != nil {
+		t.Fatalf("Qualifying: %v", err)
+	}
+	checkContains(t, message, "**UPS!**", "round 30 of the 2023 season")
+}
+
+func TestDriverResults(t *testing.T) {
+	server := newServer(t)
+
+	message, err := DriverResults(context.Background(), server.Client(), "max_verstappen", 10)
+	if err != nil {
+		t.Fatalf("DriverResults: %v", err)
+	}
+
+	checkContains(t, message, "LAST 10 RACE RESULTS FOR MAX VERSTAPPEN", "Saudi Arabian Grand Prix", "Dutch Grand Prix")
+	if strings.Contains(message, "Belgian Grand Prix") {
+		t.Errorf("message has more than the last 10 races:\n%s", message)
+	}
+
+	// Only the total and the last races are requested, not the whole career
+	checkNotRequested(t, server, "/drivers/max_verstappen/results.json?limit=1000&offset=0")
+}
+
+func TestDriverProfile(t *testing.T) {
+	server := newServer(t)
+
+	message, err := DriverProfile(context.Background(), server.Client(), "piastri")
+	if err != nil {
+		t.Fatalf("DriverProfile: %v", err)
+	}
+	if len(message.Embeds) != 2 {
+		t.Fatalf("message has %d embeds, want the profile and the career", len(message.Embeds))
+	}
+
+	if title := message.Embeds[0].Title; title != "Oscar Piastri" {
+		t.Errorf("profile title is %q, want Oscar Piastri", title)
+	}
+
+	want := map[string]string{"Starts": "2", "Wins": "0", "Poles": "0", "DNFs": "1", "Teams": "McLaren"}
+	for _, field := range message.Embeds[1].Fields {
+		if value, ok := want[field.Name]; ok && field.Value != value {
+			t.Errorf("%s is %s, want %s", field.Name, field.Value, value)
+		}
+	}
+}
+
+func TestDriverProfileBeforeQualifyingData(t *testing.T) {
+	tests := []struct {
+		driverID  string
+		seasons   []string
+		wantPoles string
+	}{
+		// The career ended before the first season with qualifying results
+		{"prost", []string{"1980", "1993"}, ""},
+		{"senna", []string{"1984", "1994"}, "Poles (since 1994)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driverID, func(t *testing.T) {
+			server := newServer(t)
+			driver := ergast.Driver{DriverID: tt.driverID, FamilyName: tt.driverID, DateOfBirth: "1960-03-21"}
+			server.SetReply("/drivers.json", ergast.MRReply{MRData: ergast.MRData{
+				Total:       "1",
+				DriverTable: ergast.DriverTable{Drivers: []ergast.Driver{driver}},
+			}})
+
+			var races []ergast.Race
+			for _, season := range tt.seasons {
+				races = append(races, ergast.Race{Season: season, Results: []ergast.RaceResult{{Position: "1", PositionText: "1", Driver: driver}}})
+			}
+			server.SetReply("/drivers/"+tt.driverID+"/results.json", ergast.MRReply{MRData: ergast.MRData{
+				Total:     strconv.Itoa(len(races)),
+				RaceTable: ergast.RaceTable{Races: races},
+			}})
+			server.SetReply("/drivers/"+tt.driverID+"/qualifying/1.json", ergast.MRReply{MRData: ergast.MRData{Total: "3"}})
+
+			message, err := DriverProfile(context.Background(), server.Client(), tt.driverID)
+			if err != nil {
+				t.Fatalf("DriverProfile: %v", err)
+			}
+
+			fields := make(map[string]string)
+			for _, embed := range message.Embeds {
+				for _, field := range embed.Fields {
+					fields[field.Name] = field.Value
+				}
+			}
+
+			if _, ok := fields["Poles"]; ok {
+				t.Errorf("poles are shown as a career total, without qualifying data of the whole career")
+			}
+			if tt.wantPoles != "" && fields[tt.wantPoles] != "3" {
+				t.Errorf("%s is %q, want 3", tt.wantPoles, fields[tt.wantPoles])
+			}
+			if tt.wantPoles == "" {
+				checkNotRequested(t, server, "/qualifying/")
+			}
+
+			// The driver might have passed away, which the data doesn't tell
+			if _, ok := fields["Age"]; ok {
+				t.Errorf("age is shown for a driver who didn't race recently")
+			}
+			if fields["Born"] != "1960-03-21" {
+				t.Errorf("date of birth is %q, want 1960-03-21", fields["Born"])
+			}
+		})
+	}
+}
+
+func TestDriverProfileOfMissingDriver(t *testing.T) {
+	server := newServer(t)
+
+	message, err := DriverProfile(context.Background(), server.Client(), "pastri")
+	if err != nil {
+		t.Fatalf("DriverProfile: %v", err)
+	}
+	checkContains(t, message.Content, "No driver with id 'pastri' was found", "piastri")
+}
+
+func TestConstructorProfile(t *testing.T) {
+	server := newServer(t)
+
+	message, err := ConstructorProfile(context.Background(), server.Client(), "red_bull")
+	if err != nil {
+		t.Fatalf("ConstructorProfile: %v", err)
+	}
+
+	// Red Bull leads the 2023 season, whose title is not decided yet
+	want := map[string]string{"Wins": "94", "Podiums": "223", "Constructors titles": "5 (2010, 2011, 2012, 2013, 2022)"}
+	for _, field := range message.Embeds[1].Fields {
+		if value, ok := want[field.Name]; ok && field.Value != value {
+			t.Errorf("%s is %s, want %s", field.Name, field.Value, value)
+		}
+	}
+}
+
+func TestDriverStandings(t *testing.T) {
+	server := newServer(t)
+
+	message, err := DriverStandings(context.Background(), server.Client(), "2023")
+	if err != nil {
+		t.Fatalf("DriverStandings: %v", err)
+	}
+
+	checkContains(t, message, "**DRIVERS CHAMPIONSHIP 2023**", "Standings after round 1", "Max Verstappen", "Oscar Piastri")
+	checkLength(t, message)
+}
+
+func TestDriverStandingsOfCrowdedSeason(t *testing.T) {
+	server := newServer(t)
+
+	// 46 drivers took part in the 1994 championship
+	standings := ergast.StandingsList{Season: "1994", Round: "16"}
+	for i := 1; i <= 46; i++ {
+		points := "0"
+		if i <= 28 {
+			points = strconv.Itoa(100 - i*3)
+		}
+		standings.DriverStandings = append(standings.DriverStandings, ergast.DriverStanding{
+			Position:     strconv.Itoa(i),
+			PositionText: strconv.Itoa(i),
+			Points:       points,
+			Wins:         "0",
+			Driver:       ergast.Driver{GivenName: "Driver", FamilyName: fmt.Sprintf("Number %d", i)},
+			Constructors: []ergast.Constructor{{Name: "Constructor"}},
+		})
+	}
+	server.SetReply("/1994/driverStandings.json", ergast.MRReply{MRData: ergast.MRData{
+		Total:          "46",
+		StandingsTable: ergast.StandingsTable{StandingsLists: []ergast.StandingsList{standings}},
+	}})
+
+	message, err := DriverStandings(context.Background(), server.Client(), "1994")
+	if err != nil {
+		t.Fatalf("DriverStandings: %v", err)
+	}
+
+	// Drivers without points are listed too, in as many messages as needed
+	for _, standing := range standings.DriverStandings {
+		checkContains(t, message, standing.Driver.FullName()+" ")
+	}
+
+	messages := SplitMessage(message, MaxMessageLength)
+	if len(messages) < 2 {
+		t.Errorf("got %d messages, want the standings split in several", len(messages))
+	}
+	checkMessages(t, messages, MaxMessageLength)
+}
+
+func TestPitStopsOfBusyRace(t *testing.T) {
+	server := newServer(t)
+
+	drivers := []string{"max_verstappen", "perez", "alonso", "sainz", "hamilton", "stroll", "russell", "bottas", "gasly", "albon"}
+	var stops []ergast.PitStop
+	for i := 0; i < 60; i++ {
+		stops = append(stops, ergast.PitStop{
+			DriverID: drivers[i%len(drivers)],
+			Lap:      strconv.Itoa(i + 1),
+			Stop:     strconv.Itoa(i/len(drivers) + 1),
+			Duration: fmt.Sprintf("2%d.%03d", i%10, i),
+		})
+	}
+	server.SetReply("/2023/1/pitstops.json", ergast.MRReply{MRData: ergast.MRData{
+		Total:     "60",
+		RaceTable: ergast.RaceTable{Races: []ergast.Race{{Season: "2023", Round: "1", PitStops: stops}}},
+	}})
+
+	message, err := PitStops(context.Background(), server.Client(), "2023", "1")
+	if err != nil {
+		t.Fatalf("PitStops: %v", err)
+	}
+
+	checkContains(t, message, "**PIT STOPS OF THE 2023 BAHRAIN GRAND PRIX**", "**TEAM SUMMARY**", "Red Bull", "Williams")
+
+	messages := SplitMessage(message, MaxMessageLength)
+	if len(messages) < 2 {
+		t.Fatalf("got %d messages, want the summary and the stops in separate messages", len(messages))
+	}
+	checkMessages(t, messages, MaxMessageLength)
+	if !strings.HasPrefix(messages[0], "**TEAM SUMMARY**") || strings.Contains(messages[0], "PIT STOPS") {
+		t.Errorf("first message isn't the team summary alone:\n%s", messages[0])
+	}
+
+	// Every stop is listed
+	stopsMessages := strings.Join(messages[1:], "")
+	for _, stop := range stops {
+		checkContains(t, stopsMessages, stop.Duration)
+	}
+}
+
+func TestLastRace(t *testing.T) {
+	server := newServer(t)
+
+	message, err := LastRace(context.Background(), server.Client(), "!f1")
+	if err != nil {
+		t.Fatalf("LastRace: %v", err)
+	}
+
+	checkContains(t, message, "**LAST RACE RESULTS**", "Bahrain Grand Prix", "Max Verstappen")
+	if strings.Contains(message, "sprint") {
+		t.Errorf("message mentions a sprint, but the race weekend had none:\n%s", message)
+	}
+}
+
+func TestLastRaceWithSprint(t *testing.T) {
+	server := newServer(t)
+	server.SetReply("/2023/1/sprint.json", ergast.MRReply{MRData: ergast.MRData{
+		Total:     "1",
+		RaceTable: ergast.RaceTable{Races: []ergast.Race{{Season: "2023", Round: "1", SprintResults: []ergast.RaceResult{{Position: "1"}}}}},
+	}})
+
+	message, err := LastRace(context.Background(), server.Client(), "/f1")
+	if err != nil {
+		t.Fatalf("LastRace: %v", err)
+	}
+	checkContains(t, message, "Type `/f1 sprint`")
+}
+
+func TestSprintFallsBackToLatestSprint(t *testing.T) {
+	server := newServer(t)
+	empty := ergast.MRReply{MRData: ergast.MRData{RaceTable: ergast.RaceTable{Season: "2023"}}}
+	session := func(date string) *ergast.DateTime {
+		return &ergast.DateTime{Date: date, Time: "13:30:00Z"}
+	}
+	server.SetReply("/current/last/sprint.json", empty)
+	server.SetReply("/current.json", ergast.MRReply{MRData: ergast.MRData{
+		Total: "3",
+		RaceTable: ergast.RaceTable{Season: "2023", Races: []ergast.Race{
+			{Season: "2023", Round: "3", RaceName: "Australian Grand Prix"},
+			{Season: "2023", Round: "4", RaceName: "Azerbaijan Grand Prix", Sprint: session("2023-04-29")},
+			{Season: "2023", Round: "5", RaceName: "Miami Grand Prix"},
+		}},
+	}})
+	server.SetReply("/2023/4/sprint.json", ergast.MRReply{MRData: ergast.MRData{
+		Total: "1",
+		RaceTable: ergast.RaceTable{Races: []ergast.Race{{
+			Season:        "2023",
+			Round:         "4",
+			RaceName:      "Azerbaijan Grand Prix",
+			SprintResults: []ergast.RaceResult{{PositionText: "1", Driver: ergast.Driver{GivenName: "Sergio", FamilyName: "Pérez"}}},
+		}}},
+	}})
+
+	message, err := Sprint(context.Background(), server.Client())
+	if err != nil {
+		t.Fatalf("Sprint: %v", err)
+	}
+	checkContains(t, message, "2023 Azerbaijan Grand Prix", "Sergio Pérez")
+
+	// Without any sprint in the season, the user is told so
+	server.SetReply("/2023/4/sprint.json", empty)
+	message, err = Sprint(context.Background(), server.Client())
+	if err != nil {
+		t.Fatalf("Sprint without sprints in the season: %v", err)
+	}
+	checkContains(t, message, "No sprint was held")
+}
+
+func TestOffSeason(t *testing.T) {
+	server := newServer(t)
+	empty := ergast.MRReply{MRData: ergast.MRData{RaceTable: ergast.RaceTable{Season: "2024"}}}
+	for _, endpoint := range []string{"/current/next.json", "/current/last/results.json", "/current.json"} {
+		server.SetReply(endpoint, empty)
+	}
+	data := server.Client()
+
+	next, err := NextRace(context.Background(), data)
+	if err != nil {
+		t.Fatalf("NextRace: %v", err)
+	}
+	checkContains(t, next.Content, "There are no more races scheduled")
+
+	last, err := LastRace(context.Background(), data, "!f1")
+	if err != nil {
+		t.Fatalf("LastRace: %v", err)
+	}
+	checkContains(t, last, "No race was held in the current season yet")
+
+	calendar, err := CurrentSeason(context.Background(), data)
+	if err != nil {
+		t.Fatalf("CurrentSeason: %v", err)
+	}
+	checkContains(t, calendar, "not available yet")
+}
+
+func TestNextRace(t *testing.T) {
+	server := newServer(t)
+
+	message, err := NextRace(context.Background(), server.Client())
+	if err != nil {
+		t.Fatalf("NextRace: %v", err)
+	}
+
+	checkContains(t, message.Content, "NEXT RACE INFORMATION")
+	if len(message.Embeds) == 0 || len(message.Embeds[0].Fields) == 0 || message.Embeds[0].Fields[0].Value != "Saudi Arabian Grand Prix" {
+		t.Errorf("message doesn't show the Saudi Arabian Grand Prix as the next race")
+	}
+}
+
+func TestHelp(t *testing.T) {
+	message, err := Help("!f1")
+	if err != nil {
+		t.Fatalf("Help: %v", err)
+	}
+	if len(message.Embeds) != 1 {
+		t.Fatalf("help has %d embeds, want 1", len(message.Embeds))
+	}
+
+	embed := message.Embeds[0]
+	if len(embed.Fields) != len(Commands()) {
+		t.Errorf("help has %d fields, want one for each of the %d commands", len(embed.Fields), len(Commands()))
+	}
+	checkContains(t, embed.Description, "!f1")
+
+	// Discord rejects embeds with more than 6000 characters
+	length := utf8.RuneCountInString(embed.Title + embed.Description)
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name + field.Value)
+		if utf8.RuneCountInString(field.Value) > maxEmbedFieldLength {
+			t.Errorf("field %s is longer than %d characters", field.Name, maxEmbedFieldLength)
+		}
+	}
+	if length > 6000 {
+		t.Errorf("help has %d characters, more than the 6000 discord accepts in an embed", length)
+	}
+}
+
+func TestHelpOfCommand(t *testing.T) {
+	for _, name := range []string{"results", "calendar"} {
+		message, err := Dispatch(context.Background(), nil, "!f1", "help", name)
+		if err != nil {
+			t.Fatalf("help %s: %v", name, err)
+		}
+		command := FindCommand(name)
+		if fields := message.Embeds[0].Fields; len(fields) != 1 || !strings.HasPrefix(fields[0].Name, command.Name) {
+			t.Errorf("help %s has fields %+v, want only the %s command", name, fields, command.Name)
+		}
+	}
+
+	_, err := Dispatch(context.Background(), nil, "!f1", "help", "podium")
+	if err == nil || !strings.Contains(err.Error(), "`!f1 help`") {
+		t.Errorf("help of unknown command returned %v, want a pointer to the help", err)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	server := newServer(t)
+	data := server.Client()
+
+	message, err := Dispatch(context.Background(), data, "!f1", "results", "race", "2023", "1")
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	checkContains(t, message.Content, "**2023 BAHRAIN GRAND PRIX RESULTS**")
+
+	_, err = Dispatch(context.Background(), data, "!f1", "results", "race", "2023")
+	if err == nil || !strings.Contains(err.Error(), "Usage: `!f1 results race <season> <round|circuit>`") {
+		t.Errorf("Dispatch with missing arguments returned %v, want the usage of the command", err)
+	}
+
+	_, err = Dispatch(context.Background(), data, "/f1", "podium")
+	if err == nil || !strings.Contains(err.Error(), "`/f1 help`") {
+		t.Errorf("Dispatch of unknown command returned %v, want a pointer to the help", err)
+	}
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ConstructorProfile performs the actions for the "constructor <constructorID>" command sent to the bot,
+// which shows a summary of the history of a constructor.
+func ConstructorProfile(ctx context.Context, data DataSource, args ...string) (*discordgo.MessageSend, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("command 'constructor' needs a constructorID as an argument")
+	}
+
+	constructorID := args[0]
+
+	// Get constructors
+	constructorTable, err := data.Constructors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of constructors from ergast: %w", err)
+	}
+
+	constructor, ok := constructorTable.FindConstructor(constructorID)
+	if !ok {
+		return &discordgo.MessageSend{Content: suggestID("constructor", constructorID, constructorTable.ConstructorIDs())}, nil
+	}
+
+	// Get constructor history from the API
+	seasonTable, err := data.RequestConstructorSeasons(ctx, constructorID)
+	if err != nil {
+		return nil, fmt.Errorf("requesting constructor seasons to ergast: %w", err)
+	}
+
+	driverTable, err := data.RequestConstructorDrivers(ctx, constructorID)
+	if err != nil {
+		return nil, fmt.Errorf("requesting constructor drivers to ergast: %w", err)
+	}
+
+	championships, err := data.RequestConstructorChampionships(ctx, constructorID)
+	if err != nil {
+		return nil, fmt.Errorf("requesting constructor championships to ergast: %w", err)
+	}
+
+	var finishes [3]int
+	for i := range finishes {
+		finishes[i], err = data.RequestConstructorPositionCount(ctx, constructorID, strconv.Itoa(i+1))
+		if err != nil {
+			return nil, fmt.Errorf("requesting constructor results to ergast: %w", err)
+		}
+	}
+
+	// Build message
+	var message discordgo.MessageSend
+	var profileEmbed discordgo.MessageEmbed
+	var statsEmbed discordgo.MessageEmbed
+
+	message.Embeds = append(message.Embeds, &profileEmbed)
+	message.Embeds = append(message.Embeds, &statsEmbed)
+
+	profileEmbed.Title = constructor.Name
+	profileEmbed.URL = constructor.URL
+
+	seasons := seasonTable.Seasons
+	profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Nationality",
+		Value:  constructor.Nationality,
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "First season",
+		Value:  seasons[0].Year,
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Last season",
+		Value:  seasons[len(seasons)-1].Year,
+		Inline: true,
+	})
+
+	statsEmbed.Title = "History"
+
+	var titles []string
+	for _, standings := range championships.StandingsLists {
+		titles = append(titles, standings.Season)
+	}
+
+	titlesValue := strconv.Itoa(len(titles))
+	if len(titles) > 0 {
+		titlesValue += fmt.Sprintf(" (%s)", strings.Join(titles, ", "))
+	}
+
+	statsEmbed.Fields = append(statsEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Seasons",
+		Value:  strconv.Itoa(len(seasons)),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Wins",
+		Value:  strconv.Itoa(finishes[0]),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Podiums",
+		Value:  strconv.Itoa(finishes[0] + finishes[1] + finishes[2]),
+		Inline: true,
+	}, &discordgo.MessageEmbedField{
+		Name:   "Constructors titles",
+		Value:  TruncateText(titlesValue, maxEmbedFieldLength),
+		Inline: false,
+	})
+
+	var drivers []string
+	for _, driver := range driverTable.Drivers {
+		drivers = append(drivers, driver.FullName())
+	}
+
+	statsEmbed.Fields = append(statsEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Drivers (%d)", len(drivers)),
+		Value:  TruncateText(strings.Join(drivers, ", "), maxEmbedFieldLength),
+		Inline: false,
+	})
+
+	return &message, nil
+}
@@ -1,17 +1,32 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"f1-discord-bot/ergast"
 )
 
-// CurrentSeason builds the message for the "current" command
-func CurrentSeason() (string, error) {
-	// Get next race from the API
-	rt, err := ergast.CurrentSeason()
+// CurrentSeason builds the message for the "current [season]" command.
+// Without arguments, it shows the races for the current season. When a season is given,
+// the winner of each race already held is also shown.
+func CurrentSeason(ctx context.Context, data DataSource, args ...string) (string, error) {
+	switch len(args) {
+	case 0:
+	case 1:
+		return SeasonCalendar(ctx, data, args[0])
+	default:
+		return "", fmt.Errorf("invalid number of arguments for the command 'current'")
+	}
+
+	// Get races for the current season from the API
+	rt, err := data.CurrentSeason(ctx)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return "The calendar of the current season is not available yet.", nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("requesting last race to ergast: %v", err)
+		return "", fmt.Errorf("requesting current season to ergast: %w", err)
 	}
 
 	// Buld message
@@ -21,23 +36,77 @@ func CurrentSeason() (string, error) {
 	m.SetTableHeader("Round", "Circuit", "Location", "Country", "Time")
 
 	for _, race := range rt.Races {
-		var localTimeStr string
-		// Parse race time
-		gpRFC3339Time := fmt.Sprintf("%sT%s", race.Date, race.Time)
-
-		t, err := ParseRFC3339InLocation(gpRFC3339Time, "Europe/Lisbon")
-		if err != nil {
-			localTimeStr = gpRFC3339Time
-		} else {
-			localTimeStr = t.Format("02 Jan 15:04 MST")
+		m.AddRow(race.Round,
+			race.Circuit.CircuitName,
+			race.Circuit.Location.Locality,
+			race.Circuit.Location.Country,
+			calendarTime(race))
+	}
+
+	return m.String(), nil
+}
+
+// SeasonCalendar builds the message for the "current <season>" and "calendar <season>" commands
+func SeasonCalendar(ctx context.Context, data DataSource, season string) (string, error) {
+	// Get seasons
+	seasonTable, err := data.Seasons(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting list of seasons from ergast: %w", err)
+	}
+
+	if season != "current" && !seasonTable.HasSeason(season) {
+		first := seasonTable.Seasons[0].Year
+		last := seasonTable.Seasons[len(seasonTable.Seasons)-1].Year
+		return fmt.Sprintf("**UPS!**\nNo season '%s' was found.\nSeasons available go from %s to %s.", season, first, last), nil
+	}
+
+	// Get races for the season from the API
+	rt, err := data.RequestSeason(ctx, season)
+	if err != nil {
+		return "", fmt.Errorf("requesting season %s to ergast: %w", season, err)
+	}
+
+	// Get winners of the races already held. A season that didn't start yet has no winners.
+	winners := make(map[string]string)
+	winnersTable, err := data.RequestSeasonWinners(ctx, season)
+	if err != nil && !errors.Is(err, ergast.ErrNoRaces) {
+		return "", fmt.Errorf("requesting winners of season %s to ergast: %w", season, err)
+	}
+	for _, race := range winnersTable.Races {
+		if len(race.Results) > 0 {
+			winners[race.Round] = race.Results[0].Driver.FullName()
 		}
+	}
 
+	// Buld message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("Races for the %s season", rt.Season)
+	m.SetTableHeader("Round", "Circuit", "Country", "Time", "Winner")
+
+	for _, race := range rt.Races {
 		m.AddRow(race.Round,
 			race.Circuit.CircuitName,
-			race.Circuit.Location.Locality,
 			race.Circuit.Location.Country,
-			localTimeStr)
+			calendarTime(race),
+			winners[race.Round])
 	}
 
 	return m.String(), nil
 }
+
+// calendarTime returns the time of a race formatted for the calendar.
+// Older races don't have a start time, in which case only the date is returned.
+func calendarTime(race ergast.Race) string {
+	if race.Time == "" {
+		return race.Date
+	}
+
+	gpRFC3339Time := fmt.Sprintf("%sT%s", race.Date, race.Time)
+
+	t, err := ParseRFC3339InLocation(gpRFC3339Time, "Europe/Lisbon")
+	if err != nil {
+		return gpRFC3339Time
+	}
+	return t.Format("02 Jan 15:04 MST")
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+
+	"f1-discord-bot/ergast"
+)
+
+// DataSource is where the commands get their data from. Commands receive an implementation,
+// so they can be backed by the ergast API, the CSV dump of its database or a fake.
+type DataSource interface {
+	// Races
+	RequestNextRace(ctx context.Context) (ergast.Race, error)
+	RequestLastRace(ctx context.Context) (ergast.Race, error)
+	RequestRaceResults(ctx context.Context, season, round string) (ergast.Race, error)
+	RequestLastQualifying(ctx context.Context) (ergast.Race, error)
+	RequestQualifying(ctx context.Context, season, round string) (ergast.Race, error)
+	RequestLastSprint(ctx context.Context) (ergast.Race, error)
+	RequestSprint(ctx context.Context, season, round string) (ergast.Race, error)
+	RequestLaps(ctx context.Context, season, round, driverID string) (ergast.Race, error)
+	RequestPitStops(ctx context.Context, season, round string) (ergast.Race, error)
+
+	// Results of circuits, drivers and constructors
+	RequestCircuitResults(ctx context.Context, circuitID string) (ergast.RaceTable, error)
+	RequestDriverResults(ctx context.Context, driverID string) (ergast.RaceTable, error)
+	RequestDriverPoleCount(ctx context.Context, driverID string) (int, error)
+	RequestDriverLastResults(ctx context.Context, driverID string, n int) (ergast.RaceTable, error)
+	RequestConstructorLastResults(ctx context.Context, constructorID string, n int) (ergast.RaceTable, error)
+	RequestConstructorSeasons(ctx context.Context, constructorID string) (ergast.SeasonTable, error)
+	RequestConstructorDrivers(ctx context.Context, constructorID string) (ergast.DriverTable, error)
+	RequestConstructorChampionships(ctx context.Context, constructorID string) (ergast.StandingsTable, error)
+	RequestConstructorPositionCount(ctx context.Context, constructorID string, position string) (int, error)
+
+	// Seasons and standings
+	CurrentSeason(ctx context.Context) (ergast.RaceTable, error)
+	RequestSeason(ctx context.Context, season string) (ergast.RaceTable, error)
+	RequestSeasonWinners(ctx context.Context, season string) (ergast.RaceTable, error)
+	RequestDriverStandings(ctx context.Context, season string) (ergast.StandingsList, error)
+	RequestConstructorStandings(ctx context.Context, season string) (ergast.StandingsList, error)
+
+	// Lookups of seasons, drivers, circuits and constructors
+	Seasons(ctx context.Context) (ergast.SeasonTable, error)
+	Drivers(ctx context.Context) (ergast.DriverTable, error)
+	Circuits(ctx context.Context) (ergast.CircuitTable, error)
+	Constructors(ctx context.Context) (ergast.ConstructorTable, error)
+}
+
+var _ DataSource = (*ergast.Client)(nil)
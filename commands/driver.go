@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"f1-discord-bot/ergast"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// nonStartStatuses are the statuses of results in which the driver didn't take the start of the race
+var nonStartStatuses = map[string]bool{
+	"Did not qualify":    true,
+	"Did not prequalify": true,
+	"Did not start":      true,
+	"Withdrew":           true,
+}
+
+// DriverProfile performs the actions for the "driver <driverID>" command sent to the bot,
+// which shows information about a driver along with their career statistics.
+func DriverProfile(ctx context.Context, data DataSource, args ...string) (*discordgo.MessageSend, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("command 'driver' needs a driverID as an argument")
+	}
+
+	driverID := args[0]
+
+	// Get drivers
+	driverTable, err := data.Drivers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of drivers from ergast: %w", err)
+	}
+
+	driver, ok := driverTable.FindDriver(driverID)
+	if !ok {
+		return &discordgo.MessageSend{Content: suggestID("driver", driverID, driverTable.DriverIDs())}, nil
+	}
+
+	// Get driver results from the API
+	raceTable, err := data.RequestDriverResults(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("requesting driver results to ergast: %w", err)
+	}
+
+	stats := DriverCareerStats(raceTable)
+
+	// The grid of a race doesn't tell who got the pole, since it includes penalties,
+	// so poles are counted from the qualifying results. Those only go back to
+	// ergast.FirstQualifyingSeason, so poles are left out for careers that ended before.
+	var polesName string
+	if stats.LastSeason >= ergast.FirstQualifyingSeason {
+		polesName = "Poles"
+		if stats.FirstSeason < ergast.FirstQualifyingSeason {
+			polesName = fmt.Sprintf("Poles (since %d)", ergast.FirstQualifyingSeason)
+		}
+
+		stats.Poles, err = data.RequestDriverPoleCount(ctx, driverID)
+		if err != nil {
+			return nil, fmt.Errorf("requesting driver poles to ergast: %w", err)
+		}
+	}
+
+	// Build message
+	var message discordgo.MessageSend
+	var profileEmbed discordgo.MessageEmbed
+	var statsEmbed discordgo.MessageEmbed
+
+	message.Embeds = append(message.Embeds, &profileEmbed)
+	message.Embeds = append(message.Embeds, &statsEmbed)
+
+	profileEmbed.Title = driver.FullName()
+	profileEmbed.URL = driver.URL
+
+	profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Nationality",
+		Value:  driver.Nationality,
+		Inline: true,
+	})
+
+	// There is no date of death in the data, so the age is only shown for drivers who raced recently
+	now := time.Now()
+	if age, err := Age(driver.DateOfBirth, now); err == nil && stats.LastSeason >= now.Year()-1 {
+		profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Age",
+			Value:  fmt.Sprintf("%d (born %s)", age, driver.DateOfBirth),
+			Inline: true,
+		})
+	} else if driver.DateOfBirth != "" {
+		profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Born",
+			Value:  driver.DateOfBirth,
+			Inline: true,
+		})
+	}
+
+	if driver.Code != "" {
+		profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Code",
+			Value:  driver.Code,
+			Inline: true,
+		})
+	}
+
+	if driver.PermanentNumber != "" {
+		profileEmbed.Fields = append(profileEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Number",
+			Value:  driver.PermanentNumber,
+			Inline: true,
+		})
+	}
+
+	statsEmbed.Title = "Career"
+
+	for _, stat := range []struct {
+		name  string
+		value string
+	}{
+		{"Starts", strconv.Itoa(stats.Starts)},
+		{"Wins", strconv.Itoa(stats.Wins)},
+		{"Podiums", strconv.Itoa(stats.Podiums)},
+		{polesName, strconv.Itoa(stats.Poles)},
+		{"Points", strconv.FormatFloat(stats.Points, 'f', -1, 64)},
+		{"DNFs", strconv.Itoa(stats.DNFs)},
+	} {
+		if stat.name == "" {
+			continue
+		}
+		statsEmbed.Fields = append(statsEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   stat.name,
+			Value:  stat.value,
+			Inline: true,
+		})
+	}
+
+	if len(stats.Teams) > 0 {
+		statsEmbed.Fields = append(statsEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Teams",
+			Value:  strings.Join(stats.Teams, ", "),
+			Inline: false,
+		})
+	}
+
+	return &message, nil
+}
+
+// CareerStats contains aggregated statistics about the career of a driver
+type CareerStats struct {
+	Starts  int
+	Wins    int
+	Podiums int
+	Poles   int
+	Points  float64
+	DNFs    int
+	// Teams the driver drove for, in chronological order
+	Teams []string
+	// FirstSeason and LastSeason are the seasons of the first and last races of the driver
+	FirstSeason int
+	LastSeason  int
+}
+
+// DriverCareerStats computes the career statistics of a driver from the results of all their races.
+// Poles are not part of the results, so they are left for the caller to fill.
+func DriverCareerStats(raceTable ergast.RaceTable) CareerStats {
+	var stats CareerStats
+	seenTeams := make(map[string]bool)
+
+	for _, race := range raceTable.Races {
+		if season, err := race.SeasonYear(); err == nil {
+			if stats.FirstSeason == 0 || season < stats.FirstSeason {
+				stats.FirstSeason = season
+			}
+			if season > stats.LastSeason {
+				stats.LastSeason = season
+			}
+		}
+
+		for _, result := range race.Results {
+			if !seenTeams[result.Constructor.ConstructorID] {
+				seenTeams[result.Constructor.ConstructorID] = true
+				stats.Teams = append(stats.Teams, result.Constructor.Name)
+			}
+
+			points, _ := result.PointsValue()
+			stats.Points += points
+
+			if nonStartStatuses[result.Status] {
+				continue
+			}
+			stats.Starts++
+
+			switch result.Position {
+			case "1":
+				stats.Wins++
+				stats.Podiums++
+			case "2", "3":
+				stats.Podiums++
+			}
+
+			if result.PositionText == "R" {
+				stats.DNFs++
+			}
+		}
+	}
+
+	return stats
+}
+
+// Age returns the age at a given time of someone born at the given date,
+// with the date in the format "2006-01-02"
+func Age(dateOfBirth string, now time.Time) (int, error) {
+	birth, err := time.Parse("2006-01-02", dateOfBirth)
+	if err != nil {
+		return 0, fmt.Errorf("parsing date of birth '%s': %v", dateOfBirth, err)
+	}
+
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	return age, nil
+}
@@ -1,18 +1,69 @@
 package commands
 
-import "fmt"
-
-// Help performs the actions for the "help" command sent to the bot,
-// which informs the user about the usage and commands available
-func Help(prefix string) string {
-	return fmt.Sprintf("usage: %s [command] [command_args...]\n", prefix) +
-		`Available commands:
-    - **help** - shows this message
-	- **next** - shows information about the next race
-	- **last** - shows information about the last race
-	- **current** - shows races for the current season
-	- **results** - shows information about results
-		- **results circuit <circuit>** - shows historical information about the winners at a given circuit for the last years
-		- **results driver <driver>** - shows last results for a driver
-`
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxEmbedFields is the maximum number of fields discord accepts in an embed
+const maxEmbedFields = 25
+
+// Help performs the actions for the "help [command]" command sent to the bot,
+// which informs the user about the usage and commands available.
+// Each command is described in its own field of an embed, so the help doesn't
+// outgrow the limits of discord as commands are added. If a command is given,
+// only that command is described.
+func Help(prefix string, args ...string) (*discordgo.MessageSend, error) {
+	cmds := Commands()
+	if len(args) > 0 {
+		command := FindCommand(args[0])
+		if command == nil {
+			return nil, fmt.Errorf("command %s not recognized. Type `%s help` for a full list of commands available", args[0], prefix)
+		}
+		cmds = []*Command{command}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Available commands",
+		Description: fmt.Sprintf("usage: `%s [command] [command_args...]`", prefix),
+	}
+
+	for _, command := range cmds {
+		if len(embed.Fields) == maxEmbedFields {
+			break
+		}
+
+		var sb strings.Builder
+		sb.WriteString(command.Description + "\n")
+
+		for _, alias := range command.Aliases {
+			fmt.Fprintf(&sb, "Also available as **%s**\n", alias)
+		}
+
+		for _, subCommand := range command.SubCommands {
+			writeHelpLine(&sb, subCommand.Name, subCommand.UsageText(), subCommand.Description)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  helpName(command.Name, command.UsageText()),
+			Value: TruncateText(sb.String(), maxEmbedFieldLength),
+		})
+	}
+
+	return &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}, nil
+}
+
+// helpName returns the name of a command along with its usage
+func helpName(name, usage string) string {
+	if usage != "" {
+		name += " " + usage
+	}
+	return name
+}
+
+// writeHelpLine writes the line describing a subcommand to the help message
+func writeHelpLine(sb *strings.Builder, name, usage, description string) {
+	fmt.Fprintf(sb, "- **%s** - %s\n", helpName(name, usage), description)
 }
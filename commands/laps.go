@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"f1-discord-bot/ergast"
+)
+
+// Laps performs the actions for the "laps <season> <round> <driver>" command sent to the bot,
+// which shows the lap times of a driver in a race along with the delta to their fastest lap.
+func Laps(ctx context.Context, data DataSource, args ...string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf("command 'laps' needs a season, a round and a driverID as arguments")
+	}
+
+	season, round, driverID := args[0], args[1], args[2]
+
+	// Get drivers
+	driverTable, err := data.Drivers(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting list of drivers from ergast: %w", err)
+	}
+
+	if !driverTable.HasDriver(driverID) {
+		return suggestID("driver", driverID, driverTable.DriverIDs()), nil
+	}
+
+	// Get lap times from the API
+	race, err := data.RequestLaps(ctx, season, round, driverID)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return fmt.Sprintf("**UPS!**\nNo lap times were found for '%s' in round %s of the %s season.", driverID, round, season), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting lap times to ergast: %w", err)
+	}
+
+	// Parse lap times and find the fastest lap
+	lapTimes := make([]time.Duration, len(race.Laps))
+	fastest := -1
+	for i, lap := range race.Laps {
+		if len(lap.Timings) == 0 {
+			continue
+		}
+		lapTime, err := lap.Timings[0].LapTime()
+		if err != nil {
+			continue
+		}
+		lapTimes[i] = lapTime
+		if fastest == -1 || lapTime < lapTimes[fastest] {
+			fastest = i
+		}
+	}
+
+	if fastest == -1 {
+		return fmt.Sprintf("**UPS!**\nNo lap times were found for '%s' in round %s of the %s season.", driverID, round, season), nil
+	}
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("Lap times for %s at the %s %s", driverID, race.Season, race.RaceName)
+	m.Description = fmt.Sprintf("Fastest lap was lap %s with a %s.",
+		race.Laps[fastest].Number,
+		race.Laps[fastest].Timings[0].Time)
+
+	// The position of the driver in each lap is left out to keep the message under the discord size limit
+	m.SetTableHeader("Lap", "Time", "Delta")
+
+	for i, lap := range race.Laps {
+		if len(lap.Timings) == 0 {
+			continue
+		}
+
+		var delta string
+		switch {
+		case lapTimes[i] == 0:
+		case i == fastest:
+			delta = "-"
+		default:
+			delta = FormatGap(lapTimes[i] - lapTimes[fastest])
+		}
+
+		m.AddRow(lap.Number,
+			lap.Timings[0].Time,
+			delta)
+	}
+
+	return m.String(), nil
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"f1-discord-bot/ergast"
@@ -8,23 +10,27 @@ import (
 
 // LastRace performs the actions for the "last" command sent to the bot,
 // which informs the user about the results of the next grand prix.
-// The result is a string ready to be sent to discord.
-func LastRace() (string, error) {
+// The result is a string ready to be sent to discord. The prefix is the way the user called
+// the bot, used to point to other commands.
+func LastRace(ctx context.Context, data DataSource, prefix string) (string, error) {
 	// Get next race from the API
-	race, err := ergast.RequestLastRace()
+	race, err := data.RequestLastRace(ctx)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return "No race was held in the current season yet.", nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("requesting last race to ergast: %v", err)
+		return "", fmt.Errorf("requesting last race to ergast: %w", err)
 	}
 
 	// Parse race time
 	gpRFC3339Time := fmt.Sprintf("%sT%s", race.Date, race.Time)
 	raceTime, err := ParseRFC3339InLocation(gpRFC3339Time, "Europe/Lisbon")
 	if err != nil {
-		return "", fmt.Errorf("parsing race time: %v", err)
+		return "", fmt.Errorf("parsing race time: %w", err)
 	}
 
 	// Build message
-	var m TabularMessage
+	m := RaceResultsMessage(race)
 
 	m.Header = "Last Race results"
 	m.Description = fmt.Sprintf("The last race was the %v at %v (%v, %v). The race was on %v.\nThe results are as follow:",
@@ -34,6 +40,21 @@ func LastRace() (string, error) {
 		race.Circuit.Location.Country,
 		raceTime.Format("Monday, 02 January 2006 15:04 MST"))
 
+	message := m.String()
+
+	// Sprint weekends have a separate classification, let the user know about it
+	if sprint, err := data.RequestSprint(ctx, race.Season, race.Round); err == nil && len(sprint.SprintResults) > 0 {
+		message += fmt.Sprintf("\nThis race weekend also had a sprint. Type `%s sprint` to see its results.", prefix)
+	}
+
+	return message, nil
+}
+
+// RaceResultsMessage builds a message with the classification of a race.
+// The header and description of the message are left for the caller to fill.
+func RaceResultsMessage(race ergast.Race) TabularMessage {
+	var m TabularMessage
+
 	m.SetTableHeader("Pos", "Driver", "Constructor", "Time", "Fastest Lap", "Started")
 
 	for _, result := range race.Results {
@@ -45,5 +66,5 @@ func LastRace() (string, error) {
 			result.Grid)
 	}
 
-	return m.String(), nil
+	return m
 }
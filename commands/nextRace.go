@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,11 +14,15 @@ import (
 // NextRace performs the actions for the "next" command sent to the bot,
 // which informs the user about the next grand prix. The result is a string ready to
 // be sent to discord.
-func NextRace() (*discordgo.MessageSend, error) {
+func NextRace(ctx context.Context, data DataSource) (*discordgo.MessageSend, error) {
 	// Get next race from the API
-	race, err := ergast.RequestNextRace()
+	race, err := data.RequestNextRace(ctx)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		// Between seasons, the calendar of the next one might not be known yet
+		return &discordgo.MessageSend{Content: "There are no more races scheduled for now. The calendar of the next season is not available yet."}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("requesting next race to ergast: %v", err)
+		return nil, fmt.Errorf("requesting next race to ergast: %w", err)
 	}
 
 	// Build message
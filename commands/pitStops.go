@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"f1-discord-bot/ergast"
+)
+
+// PitStops performs the actions for the "pitstops [season round]" command sent to the bot.
+// Without arguments, it shows the pit stops of the last race.
+func PitStops(ctx context.Context, data DataSource, args ...string) (string, error) {
+	var race ergast.Race
+	var err error
+
+	// The results of the race are needed to know the team of each driver
+	switch len(args) {
+	case 0:
+		race, err = data.RequestLastRace(ctx)
+	case 2:
+		race, err = data.RequestRaceResults(ctx, args[0], args[1])
+	default:
+		return "", fmt.Errorf("command 'pitstops' needs either no arguments or a season and a round")
+	}
+	if len(args) == 2 && errors.Is(err, ergast.ErrNoRaces) {
+		return fmt.Sprintf("**UPS!**\nNo race was found for round %s of the %s season.", args[1], args[0]), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting race results to ergast: %w", err)
+	}
+
+	// Get pit stops from the API
+	stopsRace, err := data.RequestPitStops(ctx, race.Season, race.Round)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return fmt.Sprintf("**UPS!**\nNo pit stop data is available for the %s %s.", race.Season, race.RaceName), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting pit stops to ergast: %w", err)
+	}
+
+	drivers := make(map[string]ergast.RaceResult)
+	for _, result := range race.Results {
+		drivers[result.Driver.DriverID] = result
+	}
+
+	// Build list of stops
+	var stopsMessage TabularMessage
+
+	stopsMessage.Header = fmt.Sprintf("Pit stops of the %s %s", race.Season, race.RaceName)
+	stopsMessage.SetTableHeader("Lap", "Driver", "Stop", "Duration")
+
+	summaries := make(map[string]*TeamPitStopSummary)
+	var teams []string
+
+	for _, stop := range stopsRace.PitStops {
+		result := drivers[stop.DriverID]
+
+		driverName := result.Driver.Code
+		if driverName == "" {
+			driverName = stop.DriverID
+		}
+
+		stopsMessage.AddRow(stop.Lap, driverName, stop.Stop, stop.Duration)
+
+		duration, err := stop.StopDuration()
+		if err != nil {
+			continue
+		}
+
+		team := result.Constructor.Name
+		summary, ok := summaries[team]
+		if !ok {
+			summary = &TeamPitStopSummary{}
+			summaries[team] = summary
+			teams = append(teams, team)
+		}
+		summary.Add(duration)
+	}
+
+	// Build team summary, fastest teams first
+	sort.Slice(teams, func(i, j int) bool {
+		return summaries[teams[i]].Fastest < summaries[teams[j]].Fastest
+	})
+
+	var summaryMessage TabularMessage
+
+	summaryMessage.Header = "Team summary"
+	summaryMessage.SetTableHeader("Constructor", "Stops", "Fastest", "Average")
+
+	for _, team := range teams {
+		summary := summaries[team]
+		summaryMessage.AddRow(team,
+			strconv.Itoa(summary.Stops),
+			fmt.Sprintf("%.3f", summary.Fastest.Seconds()),
+			fmt.Sprintf("%.3f", summary.Average().Seconds()))
+	}
+
+	// The summary goes first, so it's sent on its own when the list of stops needs more messages
+	return summaryMessage.String() + "\n" + stopsMessage.String(), nil
+}
+
+// TeamPitStopSummary aggregates the pit stops of a team in a race
+type TeamPitStopSummary struct {
+	Stops   int
+	Fastest time.Duration
+	Total   time.Duration
+}
+
+// Add adds the duration of a pit stop to the summary
+func (s *TeamPitStopSummary) Add(duration time.Duration) {
+	if s.Stops == 0 || duration < s.Fastest {
+		s.Fastest = duration
+	}
+	s.Stops++
+	s.Total += duration
+}
+
+// Average returns the average duration of the pit stops of the team
+func (s *TeamPitStopSummary) Average() time.Duration {
+	if s.Stops == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Stops)
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"f1-discord-bot/ergast"
+)
+
+// Qualifying performs the actions for the "qualifying [season round]" command sent to the bot.
+// Without arguments, it shows the qualifying results of the last race.
+func Qualifying(ctx context.Context, data DataSource, args ...string) (string, error) {
+	var race ergast.Race
+	var err error
+
+	switch len(args) {
+	case 0:
+		race, err = data.RequestLastQualifying(ctx)
+	case 2:
+		race, err = data.RequestQualifying(ctx, args[0], args[1])
+	default:
+		return "", fmt.Errorf("command 'qualifying' needs either no arguments or a season and a round")
+	}
+	if errors.Is(err, ergast.ErrNoRaces) {
+		if len(args) == 0 {
+			return "No qualifying was held in the current season yet.", nil
+		}
+		return fmt.Sprintf("**UPS!**\nNo qualifying results were found for round %s of the %s season.", args[1], args[0]), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting qualifying results to ergast: %w", err)
+	}
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("Qualifying results for the %s %s", race.Season, race.RaceName)
+	m.Description = fmt.Sprintf("%v (%v, %v)",
+		race.Circuit.CircuitName,
+		race.Circuit.Location.Locality,
+		race.Circuit.Location.Country)
+
+	m.SetTableHeader("Pos", "Driver", "Constructor", "Q1", "Q2", "Q3", "Gap")
+
+	var pole time.Duration
+	if len(race.QualifyingResults) > 0 {
+		pole, _ = race.QualifyingResults[0].BestTime()
+	}
+
+	for _, result := range race.QualifyingResults {
+		// Zero if the driver set no time, which leaves the gap empty
+		best, _ := result.BestTime()
+		m.AddRow(result.Position,
+			result.Driver.FullName(),
+			result.Constructor.Name,
+			result.Q1,
+			result.Q2,
+			result.Q3,
+			GapToPole(best, pole))
+	}
+
+	return m.String(), nil
+}
+
+// GapToPole returns the gap between a lap time and the pole lap time, formatted for display.
+// Returns an empty string if any of the times is missing, that is, zero.
+func GapToPole(lapTime, pole time.Duration) string {
+	if lapTime == 0 || pole == 0 {
+		return ""
+	}
+	if lapTime == pole {
+		return "-"
+	}
+	return FormatGap(lapTime - pole)
+}
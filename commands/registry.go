@@ -0,0 +1,306 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RunFunc runs a command with the given arguments, returning the message to reply with.
+// The prefix is the way the user called the bot, like "!f1" or "/f1".
+type RunFunc func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error)
+
+// SearchFunc searches the values an argument can take, returning up to limit results matching a query
+type SearchFunc func(ctx context.Context, data DataSource, query string, limit int) ([]SearchResult, error)
+
+// Argument describes an argument of a command
+type Argument struct {
+	Name        string
+	Description string
+	Required    bool
+	// Search suggests values for the argument as the user types it. If nil, no values are suggested.
+	Search SearchFunc
+}
+
+// Command describes a command of the bot. A command either runs by itself or
+// is a group of subcommands, selected by its first argument.
+type Command struct {
+	Name    string
+	Aliases []string
+	// Usage describes the arguments of the command. If empty, it's generated from the arguments.
+	Usage       string
+	Description string
+	Arguments   []Argument
+	SubCommands []*Command
+	Run         RunFunc
+}
+
+// registry has the commands of the bot, in the order they are shown to the user. Commands added here are
+// dispatched, listed in the help and registered as slash commands with no further changes.
+// It's filled on init, since the help command refers back to it.
+var registry []*Command
+
+func init() {
+	seasonArg := Argument{Name: "season", Description: "Season, like 2021"}
+	roundArg := Argument{Name: "round", Description: "Round of the race in the season"}
+	driverArg := Argument{Name: "driver", Description: "Id of the driver, like max_verstappen", Required: true, Search: SearchDrivers}
+	constructorArg := Argument{Name: "constructor", Description: "Id of the constructor, like red_bull", Required: true, Search: SearchConstructors}
+	circuitArg := Argument{Name: "circuit", Description: "Id of the circuit, like monza", Required: true, Search: SearchCircuits}
+
+	required := func(arg Argument) Argument {
+		arg.Required = true
+		return arg
+	}
+
+	registry = []*Command{
+		{
+			Name:        "help",
+			Description: "shows the commands available, or the usage of a given command",
+			Arguments:   []Argument{{Name: "command", Description: "Command to show the usage of, like results"}},
+			Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+				return Help(prefix, args...)
+			},
+		},
+		{
+			Name:        "next",
+			Description: "shows information about the next race",
+			Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+				return NextRace(ctx, data)
+			},
+		},
+		{
+			Name:        "last",
+			Description: "shows information about the last race",
+			Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+				return textMessage(LastRace(ctx, data, prefix))
+			},
+		},
+		{
+			Name:        "qualifying",
+			Usage:       "[season round]",
+			Description: "shows the qualifying results of the last race, or of a given round of a season",
+			Arguments:   []Argument{seasonArg, roundArg},
+			Run:         textCommand(Qualifying),
+		},
+		{
+			Name:        "sprint",
+			Usage:       "[season round]",
+			Description: "shows the sprint results of the last race weekend, or of a given round of a season",
+			Arguments:   []Argument{seasonArg, roundArg},
+			Run:         textCommand(Sprint),
+		},
+		{
+			Name:        "laps",
+			Description: "shows the lap times of a driver in a race",
+			Arguments:   []Argument{required(seasonArg), required(roundArg), driverArg},
+			Run:         textCommand(Laps),
+		},
+		{
+			Name:        "pitstops",
+			Usage:       "[season round]",
+			Description: "shows the pit stops of the last race, or of a given round of a season, with a summary per team",
+			Arguments:   []Argument{seasonArg, roundArg},
+			Run:         textCommand(PitStops),
+		},
+		{
+			Name:        "driver",
+			Description: "shows the profile and career statistics of a driver",
+			Arguments:   []Argument{driverArg},
+			Run:         embedCommand(DriverProfile),
+		},
+		{
+			Name:        "constructor",
+			Description: "shows a summary of the history of a constructor",
+			Arguments:   []Argument{constructorArg},
+			Run:         embedCommand(ConstructorProfile),
+		},
+		{
+			Name:        "circuit",
+			Description: "shows information about a circuit and the grand prix held there",
+			Arguments:   []Argument{circuitArg},
+			Run:         embedCommand(CircuitInfo),
+		},
+		{
+			Name:        "current",
+			Aliases:     []string{"calendar"},
+			Description: "shows races for the current season, or for a given season along with the winners",
+			Arguments:   []Argument{seasonArg},
+			Run:         textCommand(CurrentSeason),
+		},
+		{
+			Name:        "results",
+			Description: "shows information about results",
+			SubCommands: []*Command{
+				{
+					Name:        "circuit",
+					Description: "shows historical information about the winners at a given circuit for the last years",
+					Arguments:   []Argument{circuitArg},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(CircuitResults(ctx, data, args[0], 10))
+					},
+				},
+				{
+					Name:        "driver",
+					Description: "shows last results for a driver",
+					Arguments:   []Argument{driverArg},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(DriverResults(ctx, data, args[0], 10))
+					},
+				},
+				{
+					Name:        "constructor",
+					Description: "shows last results for a constructor",
+					Arguments:   []Argument{constructorArg},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(ConstructorResults(ctx, data, args[0], 10))
+					},
+				},
+				{
+					Name:        "race",
+					Usage:       "<season> <round|circuit>",
+					Description: "shows the full classification of a race",
+					Arguments: []Argument{
+						required(seasonArg),
+						{Name: "round", Description: "Round of the race in the season, or id of the circuit", Required: true},
+					},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(RaceResults(ctx, data, args[0], args[1]))
+					},
+				},
+			},
+		},
+		{
+			Name:        "standings",
+			Description: "shows championship standings",
+			SubCommands: []*Command{
+				{
+					Name:        "drivers",
+					Description: "shows the drivers championship standings, for the current season by default",
+					Arguments:   []Argument{seasonArg},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(DriverStandings(ctx, data, seasonOrCurrent(args)))
+					},
+				},
+				{
+					Name:        "constructors",
+					Description: "shows the constructors championship standings, for the current season by default",
+					Arguments:   []Argument{seasonArg},
+					Run: func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+						return textMessage(ConstructorStandings(ctx, data, seasonOrCurrent(args)))
+					},
+				},
+			},
+		},
+	}
+}
+
+// Commands returns the commands of the bot, in the order they are shown to the user
+func Commands() []*Command {
+	return registry
+}
+
+// FindCommand returns the command with a given name or alias, or nil if there is none
+func FindCommand(name string) *Command {
+	return findCommand(registry, name)
+}
+
+func findCommand(commands []*Command, name string) *Command {
+	for _, command := range commands {
+		if command.Name == name {
+			return command
+		}
+		for _, alias := range command.Aliases {
+			if alias == name {
+				return command
+			}
+		}
+	}
+	return nil
+}
+
+// Dispatch runs the command with a given name or alias, returning the message to reply with.
+// The arguments select the subcommand to run, if the command has subcommands, and are checked
+// against the arguments of the command before running it.
+func Dispatch(ctx context.Context, data DataSource, prefix, name string, args ...string) (*discordgo.MessageSend, error) {
+	command := FindCommand(name)
+	if command == nil {
+		return nil, fmt.Errorf("command %s not recognized. Type `%s help` for a full list of commands available", name, prefix)
+	}
+
+	fullName := command.Name
+	for len(command.SubCommands) > 0 {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("command '%s' needs more arguments", fullName)
+		}
+
+		subCommand := findCommand(command.SubCommands, args[0])
+		if subCommand == nil {
+			return nil, fmt.Errorf("subcommand '%s' of '%s' not recognized or not yet implemented", args[0], fullName)
+		}
+
+		command, args = subCommand, args[1:]
+		fullName += " " + command.Name
+	}
+
+	var requiredArgs int
+	for _, arg := range command.Arguments {
+		if arg.Required {
+			requiredArgs++
+		}
+	}
+	if len(args) < requiredArgs || len(args) > len(command.Arguments) {
+		return nil, fmt.Errorf("invalid number of arguments for the command '%s'. Usage: `%s %s %s`", fullName, prefix, fullName, command.UsageText())
+	}
+
+	return command.Run(ctx, data, prefix, args...)
+}
+
+// UsageText returns the usage of the command, generating it from the arguments if not set.
+// Required arguments are shown as <name> and optional ones as [name].
+func (c *Command) UsageText() string {
+	if c.Usage != "" || len(c.Arguments) == 0 {
+		return c.Usage
+	}
+
+	var parts []string
+	for _, arg := range c.Arguments {
+		if arg.Required {
+			parts = append(parts, "<"+arg.Name+">")
+		} else {
+			parts = append(parts, "["+arg.Name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// textCommand adapts a command replying with text to a RunFunc
+func textCommand(fn func(ctx context.Context, data DataSource, args ...string) (string, error)) RunFunc {
+	return func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+		return textMessage(fn(ctx, data, args...))
+	}
+}
+
+// embedCommand adapts a command replying with a complex message to a RunFunc
+func embedCommand(fn func(ctx context.Context, data DataSource, args ...string) (*discordgo.MessageSend, error)) RunFunc {
+	return func(ctx context.Context, data DataSource, prefix string, args ...string) (*discordgo.MessageSend, error) {
+		return fn(ctx, data, args...)
+	}
+}
+
+// textMessage wraps the text reply of a command into a message
+func textMessage(message string, err error) (*discordgo.MessageSend, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &discordgo.MessageSend{Content: message}, nil
+}
+
+// seasonOrCurrent returns the season given in the arguments, or "current" if none was given
+func seasonOrCurrent(args []string) string {
+	if len(args) == 0 {
+		return "current"
+	}
+	return args[0]
+}
@@ -1,78 +1,32 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"f1-discord-bot/ergast"
 )
 
-// Results performs the actions for the "results" command sent to the bot
-func Results(args ...string) (string, error) {
-	if len(args) < 1 {
-		return "", fmt.Errorf("command 'results' needs more arguments")
-	}
-
-	subCommand := args[0]
-
-	switch subCommand {
-	case "circuit":
-		switch {
-		case len(args) < 2:
-			return "", fmt.Errorf("command 'results circuit' needs a circuitID as an argument")
-		case len(args) == 2:
-			message, err := CircuitResults(args[1], 10)
-			if err != nil {
-				return "", fmt.Errorf("getting circuit results: %v", err)
-			}
-			return message, nil
-		default:
-			return "", fmt.Errorf("invalid number of arguments for the command 'results'")
-		}
-
-	case "driver":
-		switch {
-		case len(args) < 2:
-			return "", fmt.Errorf("command 'results driver' needs a driverID as an argument")
-		case len(args) == 2:
-			message, err := DriverResults(args[1], 10)
-			if err != nil {
-				return "", fmt.Errorf("getting driver results: %v", err)
-			}
-			return message, nil
-		default:
-			return "", fmt.Errorf("invalid number of arguments for the command 'results'")
-		}
-	default:
-		return "", fmt.Errorf("subcommand '%s' of 'results' not recognized or not yet implemented", subCommand)
-	}
-}
-
 // CircuitResults performs the actions for the "results circuit <circuitID>" command sent to the bot
-func CircuitResults(circuitID string, n int) (string, error) {
+func CircuitResults(ctx context.Context, data DataSource, circuitID string, n int) (string, error) {
 	// Get circuits
-	circuitTable, err := ergast.Circuits()
+	circuitTable, err := data.Circuits(ctx)
 	if err != nil {
-		return "", fmt.Errorf("getting list of circuits from ergast: %v", err)
+		return "", fmt.Errorf("getting list of circuits from ergast: %w", err)
 	}
 
 	if !circuitTable.HasCircuit(circuitID) {
 		// The circuit requested was not found in the list of circuits
-		// Compute the levenshtein distances between the given argument and
-		// all the circuits to see the one the user probably meant
-		var lds LevenshteinDistances
-
-		for _, circuit := range circuitTable.Circuits {
-			lds = append(lds, LevenshteinDistance{Str1: circuitID, Str2: circuit.CircuitID})
-		}
-		lds.ComputeAll()
-		lds.SortByDistance()
-		return fmt.Sprintf("**UPS!**\nNo circuit with id '%s' was found.\nDid you mean?\n\t- %s", circuitID, lds[0].Str2), nil
+		return suggestID("circuit", circuitID, circuitTable.CircuitIDs()), nil
 	}
 
 	// Get circuit results from the API
-	raceTable, err := ergast.RequestCircuitResults(circuitID)
+	raceTable, err := data.RequestCircuitResults(ctx, circuitID)
 	if err != nil {
-		return "", fmt.Errorf("requesting circuit results to ergast: %v", err)
+		return "", fmt.Errorf("requesting circuit results to ergast: %w", err)
 	}
 
 	// Trim the first races
@@ -102,28 +56,21 @@ func CircuitResults(circuitID string, n int) (string, error) {
 }
 
 // DriverResults performs the actions for the "results driver <driverID>" command sent to the bot
-func DriverResults(driverID string, n int) (string, error) {
+func DriverResults(ctx context.Context, data DataSource, driverID string, n int) (string, error) {
 	// Get circuits
-	driverTable, err := ergast.Drivers()
+	driverTable, err := data.Drivers(ctx)
 	if err != nil {
-		return "", fmt.Errorf("getting list of circuits from ergast: %v", err)
+		return "", fmt.Errorf("getting list of circuits from ergast: %w", err)
 	}
 
 	if !driverTable.HasDriver(driverID) {
-		var lds LevenshteinDistances
-
-		for _, driver := range driverTable.Drivers {
-			lds = append(lds, LevenshteinDistance{Str1: driverID, Str2: driver.DriverID})
-		}
-		lds.ComputeAll()
-		lds.SortByDistance()
-		return fmt.Sprintf("**UPS!**\nNo driver with id '%s' was found.\nDid you mean?\n\t- %s", driverID, lds[0].Str2), nil
+		return suggestID("driver", driverID, driverTable.DriverIDs()), nil
 	}
 
 	// Get driver results from the API
-	raceTable, err := ergast.RequestDriverResults(driverID)
+	raceTable, err := data.RequestDriverLastResults(ctx, driverID, n)
 	if err != nil {
-		return "", fmt.Errorf("requesting circuit results to ergast: %v", err)
+		return "", fmt.Errorf("requesting circuit results to ergast: %w", err)
 	}
 
 	// Trim the first races
@@ -154,3 +101,132 @@ func DriverResults(driverID string, n int) (string, error) {
 
 	return m.String(), nil
 }
+
+// ConstructorResults performs the actions for the "results constructor <constructorID>" command sent to the bot
+func ConstructorResults(ctx context.Context, data DataSource, constructorID string, n int) (string, error) {
+	// Get constructors
+	constructorTable, err := data.Constructors(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting list of constructors from ergast: %w", err)
+	}
+
+	if !constructorTable.HasConstructor(constructorID) {
+		return suggestID("constructor", constructorID, constructorTable.ConstructorIDs()), nil
+	}
+
+	// Get constructor results from the API
+	raceTable, err := data.RequestConstructorLastResults(ctx, constructorID, n)
+	if err != nil {
+		return "", fmt.Errorf("requesting constructor results to ergast: %w", err)
+	}
+
+	// Trim the first races
+	nRaces := len(raceTable.Races)
+	if nRaces < n {
+		n = nRaces
+	}
+	races := raceTable.Races[(nRaces - n):]
+	constructor := races[0].Results[0].Constructor
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("LAST %d RACE RESULTS FOR %s", n, constructor.Name)
+	m.SetTableHeader("Year", "GP", "Drivers", "Pos.", "Points")
+
+	for i := len(races) - 1; i >= 0; i-- {
+		race := races[i]
+
+		var drivers, positions []string
+		var points float64
+		for _, result := range race.Results {
+			drivers = append(drivers, result.Driver.FamilyName)
+			positions = append(positions, result.PositionText)
+			p, _ := result.PointsValue()
+			points += p
+		}
+
+		m.AddRow(race.Season,
+			race.RaceName,
+			strings.Join(drivers, "/"),
+			strings.Join(positions, "/"),
+			strconv.FormatFloat(points, 'f', -1, 64))
+	}
+
+	return m.String(), nil
+}
+
+// RaceResults performs the actions for the "results race <season> <round|circuitID>" command sent to the bot
+func RaceResults(ctx context.Context, data DataSource, season, roundOrCircuit string) (string, error) {
+	// The race is looked up in the calendar of the season first, so arguments
+	// that don't match any race don't make requests to the API
+	race, found, err := findSeasonRace(ctx, data, season, roundOrCircuit)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("**UPS!**\nNo race results were found for '%s' in the %s season.", roundOrCircuit, season), nil
+	}
+
+	race, err = data.RequestRaceResults(ctx, race.Season, race.Round)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return fmt.Sprintf("**UPS!**\nNo race results were found for '%s' in the %s season.", roundOrCircuit, season), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting race results to ergast: %w", err)
+	}
+
+	// Parse race time. Older races don't have a start time, so only the date is shown for those.
+	raceDate := race.Date
+	if raceTime, err := race.TimeInLocation("Europe/Lisbon"); err == nil {
+		raceDate = raceTime.Format("Monday, 02 January 2006 15:04 MST")
+	}
+
+	// Build message
+	m := RaceResultsMessage(race)
+
+	m.Header = fmt.Sprintf("%s %s results", race.Season, race.RaceName)
+	m.Description = fmt.Sprintf("Round %v of the %v season, at %v (%v, %v). The race was on %v.\nThe results are as follow:",
+		race.Round,
+		race.Season,
+		race.Circuit.CircuitName,
+		race.Circuit.Location.Locality,
+		race.Circuit.Location.Country,
+		raceDate)
+
+	return m.String(), nil
+}
+
+// findSeasonRace finds a race in the calendar of a season, given its round or the id of its circuit.
+// The season can be a year or "current". Returns false if there is no such season or race.
+func findSeasonRace(ctx context.Context, data DataSource, season, roundOrCircuit string) (ergast.Race, bool, error) {
+	if season != "current" {
+		seasonTable, err := data.Seasons(ctx)
+		if err != nil {
+			return ergast.Race{}, false, fmt.Errorf("getting list of seasons from ergast: %w", err)
+		}
+		if !seasonTable.HasSeason(season) {
+			return ergast.Race{}, false, nil
+		}
+	}
+
+	raceTable, err := data.RequestSeason(ctx, season)
+	if errors.Is(err, ergast.ErrNoRaces) {
+		return ergast.Race{}, false, nil
+	}
+	if err != nil {
+		return ergast.Race{}, false, fmt.Errorf("requesting season calendar to ergast: %w", err)
+	}
+
+	// Rounds are numeric, anything else is assumed to be a circuit id
+	round, convErr := strconv.Atoi(roundOrCircuit)
+	for _, race := range raceTable.Races {
+		if convErr != nil && strings.EqualFold(race.Circuit.CircuitID, roundOrCircuit) {
+			return race, true, nil
+		}
+		if raceRound, err := race.RoundNumber(); convErr == nil && err == nil && raceRound == round {
+			return race, true, nil
+		}
+	}
+	return ergast.Race{}, false, nil
+}
@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"f1-discord-bot/ergast"
+
+	"github.com/agnivade/levenshtein"
+)
+
+// SearchResult is an entry found by a search of drivers, circuits or constructors
+type SearchResult struct {
+	// ID is the ergast id of the entry, like "max_verstappen"
+	ID string
+	// Name is the name shown to the user, like "Max Verstappen (VER)"
+	Name string
+}
+
+// Relevance of a match between a query and an entry, from the most to the least relevant
+const (
+	exactMatch = iota
+	prefixMatch
+	substringMatch
+	fuzzyMatch
+	noMatch
+)
+
+// maxFuzzyDistance is the maximum levenshtein distance between a query and a value for them to match.
+// Fuzzy matches are only tried for queries longer than this, otherwise almost everything would match.
+const maxFuzzyDistance = 2
+
+// searchCandidate is an entry that can be found by a search, along with the values it can be found by
+type searchCandidate struct {
+	result  SearchResult
+	fields  []string
+	current bool
+}
+
+// accentReplacer removes the accents of the letters found in the names of drivers, circuits and constructors
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss", "ý", "y",
+)
+
+// normalize prepares a value to be compared with a query, ignoring case and accents
+func normalize(value string) string {
+	return accentReplacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// matchRelevance returns how well a query matches the values of an entry.
+// A field matches by prefix if the query is the prefix of the field or of any of its words.
+func matchRelevance(query string, fields []string) int {
+	best := noMatch
+	for _, field := range fields {
+		field = normalize(field)
+		if field == "" {
+			continue
+		}
+
+		relevance := noMatch
+		switch {
+		case field == query:
+			return exactMatch
+		case strings.HasPrefix(field, query) || strings.Contains(field, " "+query) || strings.Contains(field, "_"+query):
+			relevance = prefixMatch
+		case strings.Contains(field, query):
+			relevance = substringMatch
+		case len(query) > maxFuzzyDistance && levenshtein.ComputeDistance(query, field) <= maxFuzzyDistance:
+			relevance = fuzzyMatch
+		}
+
+		if relevance < best {
+			best = relevance
+		}
+	}
+	return best
+}
+
+// search returns up to limit candidates matching a query, the most relevant first.
+// Among equally relevant candidates, the ones of the current season come first.
+// An empty query matches every candidate.
+func search(query string, candidates []searchCandidate, limit int) []SearchResult {
+	query = normalize(query)
+
+	type match struct {
+		candidate searchCandidate
+		relevance int
+	}
+
+	var matches []match
+	for _, candidate := range candidates {
+		relevance := exactMatch
+		if query != "" {
+			relevance = matchRelevance(query, candidate.fields)
+		}
+		if relevance != noMatch {
+			matches = append(matches, match{candidate: candidate, relevance: relevance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.relevance != b.relevance {
+			return a.relevance < b.relevance
+		}
+		if a.candidate.current != b.candidate.current {
+			return a.candidate.current
+		}
+		return a.candidate.result.Name < b.candidate.result.Name
+	})
+
+	var results []SearchResult
+	for i := 0; i < len(matches) && i < limit; i++ {
+		results = append(results, matches[i].candidate.result)
+	}
+	return results
+}
+
+// SearchDrivers searches drivers by id, given name, family name, code and permanent number
+func SearchDrivers(ctx context.Context, data DataSource, query string, limit int) ([]SearchResult, error) {
+	driverTable, err := data.Drivers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of drivers: %w", err)
+	}
+
+	// The drivers of the current season are the ones in the championship
+	current := make(map[string]bool)
+	if standings, err := data.RequestDriverStandings(ctx, "current"); err == nil {
+		for _, standing := range standings.DriverStandings {
+			current[standing.Driver.DriverID] = true
+		}
+	}
+
+	candidates := make([]searchCandidate, 0, len(driverTable.Drivers))
+	for _, driver := range driverTable.Drivers {
+		name := driver.FullName()
+		if driver.Code != "" {
+			name += " (" + driver.Code + ")"
+		}
+
+		candidates = append(candidates, searchCandidate{
+			result:  SearchResult{ID: driver.DriverID, Name: name},
+			fields:  []string{driver.DriverID, driver.GivenName, driver.FamilyName, driver.FullName(), driver.Code, driver.PermanentNumber},
+			current: current[driver.DriverID],
+		})
+	}
+
+	return search(query, candidates, limit), nil
+}
+
+// SearchConstructors searches constructors by id and name
+func SearchConstructors(ctx context.Context, data DataSource, query string, limit int) ([]SearchResult, error) {
+	constructorTable, err := data.Constructors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of constructors: %w", err)
+	}
+
+	// The constructors of the current season are the ones in the championship
+	current := make(map[string]bool)
+	if standings, err := data.RequestConstructorStandings(ctx, "current"); err == nil {
+		for _, standing := range standings.ConstructorStandings {
+			current[standing.Constructor.ConstructorID] = true
+		}
+	}
+
+	candidates := make([]searchCandidate, 0, len(constructorTable.Constructors))
+	for _, constructor := range constructorTable.Constructors {
+		candidates = append(candidates, searchCandidate{
+			result:  SearchResult{ID: constructor.ConstructorID, Name: constructor.Name},
+			fields:  []string{constructor.ConstructorID, constructor.Name},
+			current: current[constructor.ConstructorID],
+		})
+	}
+
+	return search(query, candidates, limit), nil
+}
+
+// SearchCircuits searches circuits by id, name, locality and country
+func SearchCircuits(ctx context.Context, data DataSource, query string, limit int) ([]SearchResult, error) {
+	circuitTable, err := data.Circuits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting list of circuits: %w", err)
+	}
+
+	// The circuits of the current season are the ones in its calendar
+	current := make(map[string]bool)
+	if raceTable, err := data.CurrentSeason(ctx); err == nil {
+		for _, race := range raceTable.Races {
+			current[race.Circuit.CircuitID] = true
+		}
+	}
+
+	candidates := make([]searchCandidate, 0, len(circuitTable.Circuits))
+	for _, circuit := range circuitTable.Circuits {
+		candidates = append(candidates, searchCandidate{
+			result:  SearchResult{ID: circuit.CircuitID, Name: circuitSearchName(circuit)},
+			fields:  []string{circuit.CircuitID, circuit.CircuitName, circuit.Location.Locality, circuit.Location.Country},
+			current: current[circuit.CircuitID],
+		})
+	}
+
+	return search(query, candidates, limit), nil
+}
+
+// circuitSearchName returns the name of a circuit along with its location
+func circuitSearchName(circuit ergast.Circuit) string {
+	if circuit.Location.Locality == "" {
+		return circuit.CircuitName
+	}
+	return fmt.Sprintf("%s (%s, %s)", circuit.CircuitName, circuit.Location.Locality, circuit.Location.Country)
+}
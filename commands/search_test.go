@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"f1-discord-bot/ergasttest"
+)
+
+func TestSearchRanking(t *testing.T) {
+	candidates := []searchCandidate{
+		{result: SearchResult{ID: "perez", Name: "Sergio Pérez (PER)"}, fields: []string{"perez", "Sergio", "Pérez", "Sergio Pérez", "PER", "11"}, current: true},
+		{result: SearchResult{ID: "max_verstappen", Name: "Max Verstappen (VER)"}, fields: []string{"max_verstappen", "Max", "Verstappen", "Max Verstappen", "VER", "33"}, current: true},
+		{result: SearchResult{ID: "verstappen", Name: "Jos Verstappen"}, fields: []string{"verstappen", "Jos", "Verstappen", "Jos Verstappen"}},
+		{result: SearchResult{ID: "hamilton", Name: "Lewis Hamilton (HAM)"}, fields: []string{"hamilton", "Lewis", "Hamilton", "Lewis Hamilton", "HAM", "44"}, current: true},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Exact matches first, then prefixes and then typos, with the drivers of the current season first
+		{"ver", []string{"max_verstappen", "verstappen", "perez"}},
+		{"verstappen", []string{"max_verstappen", "verstappen"}},
+		{"jos", []string{"verstappen"}},
+		{"33", []string{"max_verstappen"}},
+		// Accents and case are ignored
+		{"PEREZ", []string{"perez"}},
+		// Typos are forgiven in longer queries
+		{"hamiltn", []string{"hamilton"}},
+		{"xyz", nil},
+		// An empty query lists every candidate, the current ones first
+		{"", []string{"hamilton", "max_verstappen", "perez", "verstappen"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, result := range search(tt.query, candidates, 10) {
+			got = append(got, result.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if got := search("", candidates, 2); len(got) != 2 {
+		t.Errorf("search with a limit of 2 returned %d results", len(got))
+	}
+}
+
+func TestSearchDrivers(t *testing.T) {
+	server := ergasttest.NewServer(ergasttest.Fixtures())
+	defer server.Close()
+
+	// The standings of the current season are missing, so no driver is preferred
+	results, err := SearchDrivers(context.Background(), server.Client(), "hulk", 25)
+	if err != nil {
+		t.Fatalf("SearchDrivers: %v", err)
+	}
+
+	want := []SearchResult{{ID: "hulkenberg", Name: "Nico Hülkenberg (HUL)"}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("SearchDrivers(\"hulk\") = %v, want %v", results, want)
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"f1-discord-bot/ergast"
+)
+
+// Sprint performs the actions for the "sprint [season round]" command sent to the bot.
+// Without arguments, it shows the sprint results of the last race weekend, or of the
+// latest sprint of the season if the last race weekend had none.
+func Sprint(ctx context.Context, data DataSource, args ...string) (string, error) {
+	var race ergast.Race
+	var err error
+
+	switch len(args) {
+	case 0:
+		race, err = data.RequestLastSprint(ctx)
+		if errors.Is(err, ergast.ErrNoRaces) {
+			race, err = latestSprint(ctx, data)
+		}
+	case 2:
+		race, err = data.RequestSprint(ctx, args[0], args[1])
+	default:
+		return "", fmt.Errorf("command 'sprint' needs either no arguments or a season and a round")
+	}
+	if errors.Is(err, ergast.ErrNoRaces) {
+		if len(args) == 0 {
+			return "No sprint was held in the current season yet.", nil
+		}
+		return fmt.Sprintf("**UPS!**\nNo sprint results were found for round %s of the %s season.", args[1], args[0]), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("requesting sprint results to ergast: %w", err)
+	}
+
+	// Parse sprint time
+	var sprintTimeStr string
+	if race.Sprint != nil {
+		sprintTime, err := race.Sprint.TimeInLocation("Europe/Lisbon")
+		if err != nil {
+			return "", fmt.Errorf("parsing sprint time: %w", err)
+		}
+		sprintTimeStr = fmt.Sprintf(" The sprint was on %v.", sprintTime.Format("Monday, 02 January 2006 15:04 MST"))
+	}
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = "Sprint results"
+	m.Description = fmt.Sprintf("Sprint of the %v %v at %v (%v, %v).%v\nThe results are as follow:",
+		race.Season,
+		race.RaceName,
+		race.Circuit.CircuitName,
+		race.Circuit.Location.Locality,
+		race.Circuit.Location.Country,
+		sprintTimeStr)
+
+	m.SetTableHeader("Pos", "Driver", "Constructor", "Time", "Points", "Started")
+
+	for _, result := range race.SprintResults {
+		m.AddRow(result.PositionText,
+			result.Driver.FullName(),
+			result.Constructor.Name,
+			result.Time.Time,
+			result.Points,
+			result.Grid)
+	}
+
+	return m.String(), nil
+}
+
+// latestSprint returns the results of the latest sprint of the current season already held.
+// Returns ergast.ErrNoRaces if there is none.
+func latestSprint(ctx context.Context, data DataSource) (ergast.Race, error) {
+	rt, err := data.CurrentSeason(ctx)
+	if err != nil {
+		return ergast.Race{}, err
+	}
+
+	now := time.Now()
+	for i := len(rt.Races) - 1; i >= 0; i-- {
+		race := rt.Races[i]
+		if race.Sprint == nil {
+			continue
+		}
+		if sprintTime, err := race.Sprint.GoTime(); err != nil || sprintTime.After(now) {
+			continue
+		}
+
+		sprint, err := data.RequestSprint(ctx, race.Season, race.Round)
+		if errors.Is(err, ergast.ErrNoRaces) {
+			// The results of a sprint that just finished might not be available yet
+			continue
+		}
+		return sprint, err
+	}
+
+	return ergast.Race{}, ergast.ErrNoRaces
+}
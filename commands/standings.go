@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DriverStandings performs the actions for the "standings drivers [season]" command sent to the bot
+func DriverStandings(ctx context.Context, data DataSource, season string) (string, error) {
+	// Get standings from the API
+	standings, err := data.RequestDriverStandings(ctx, season)
+	if err != nil {
+		return "", fmt.Errorf("requesting driver standings to ergast: %w", err)
+	}
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("Drivers championship %s", standings.Season)
+	m.Description = fmt.Sprintf("Standings after round %s:", standings.Round)
+	m.SetTableHeader("Pos", "Driver", "Constructor", "Points", "Wins")
+
+	for _, standing := range standings.DriverStandings {
+		var constructors []string
+		for _, constructor := range standing.Constructors {
+			constructors = append(constructors, constructor.Name)
+		}
+
+		m.AddRow(standing.PositionText,
+			standing.Driver.FullName(),
+			strings.Join(constructors, "/"),
+			standing.Points,
+			standing.Wins)
+	}
+
+	return m.String(), nil
+}
+
+// ConstructorStandings performs the actions for the "standings constructors [season]" command sent to the bot
+func ConstructorStandings(ctx context.Context, data DataSource, season string) (string, error) {
+	// Get standings from the API
+	standings, err := data.RequestConstructorStandings(ctx, season)
+	if err != nil {
+		return "", fmt.Errorf("requesting constructor standings to ergast: %w", err)
+	}
+
+	// Build message
+	var m TabularMessage
+
+	m.Header = fmt.Sprintf("Constructors championship %s", standings.Season)
+	m.Description = fmt.Sprintf("Standings after round %s:", standings.Round)
+	m.SetTableHeader("Pos", "Constructor", "Nationality", "Points", "Wins")
+
+	for _, standing := range standings.ConstructorStandings {
+		m.AddRow(standing.PositionText,
+			standing.Constructor.Name,
+			standing.Constructor.Nationality,
+			standing.Points,
+			standing.Wins)
+	}
+
+	return m.String(), nil
+}
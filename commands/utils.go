@@ -8,6 +8,7 @@ import (
 	"strings"
 	tbw "text/tabwriter"
 	"time"
+	"unicode/utf8"
 
 	"github.com/agnivade/levenshtein"
 )
@@ -42,6 +43,23 @@ func (lds LevenshteinDistances) SortByDistance() {
 	})
 }
 
+// suggestID builds the message for an id of a given kind that was not found,
+// suggesting the known id closest to it, the one the user probably meant
+func suggestID(kind, id string, ids []string) string {
+	message := fmt.Sprintf("**UPS!**\nNo %s with id '%s' was found.", kind, id)
+	if len(ids) == 0 {
+		return message
+	}
+
+	lds := make(LevenshteinDistances, 0, len(ids))
+	for _, knownID := range ids {
+		lds = append(lds, LevenshteinDistance{Str1: id, Str2: knownID})
+	}
+	lds.ComputeAll()
+	lds.SortByDistance()
+	return fmt.Sprintf("%s\nDid you mean?\n\t- %s", message, lds[0].Str2)
+}
+
 // PrettyCountdount sees a Duration as a countdown for an event to happen
 // and transforms into a easily readible string representation.
 // The results contains information about if the event it's in the past or it's still to come.
@@ -98,6 +116,12 @@ func (hm *HeaderMessage) String() string {
 	return message.String()
 }
 
+// MaxMessageLength is the maximum number of characters discord accepts in the content of a message
+const MaxMessageLength = 2000
+
+// maxEmbedFieldLength is the maximum number of characters discord accepts in the value of an embed field
+const maxEmbedFieldLength = 1024
+
 // TabularMessage represents a message with an header, description and some tabular data
 type TabularMessage struct {
 	HeaderMessage
@@ -115,7 +139,8 @@ func (tm *TabularMessage) AddRow(rowData ...string) {
 	tm.TableRows = append(tm.TableRows, rowData)
 }
 
-// String returns TabularMessage for discord
+// String returns TabularMessage for discord.
+// Messages longer than what discord accepts are split with SplitMessage when sent.
 func (tm *TabularMessage) String() string {
 	var message strings.Builder
 
@@ -176,3 +201,94 @@ func ParseDuration(d time.Duration) string {
 	}
 	return res
 }
+
+// FormatGap formats a time difference between two laps as a string like "+0.123"
+func FormatGap(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	return fmt.Sprintf("%s%.3f", sign, d.Seconds())
+}
+
+// TruncateText truncates a text to have at most maxLength characters, adding an ellipsis at the end
+// if the text had to be truncated
+func TruncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-3]) + "..."
+}
+
+// codeFence opens and closes code blocks in discord messages
+const codeFence = "```"
+
+// SplitMessage splits the content of a message into messages of at most maxLength characters.
+// Messages are split after a code block when possible, keeping tables whole, and otherwise between
+// lines. A code block split in two is closed, and opened again in the next message with its first
+// line, which is the header of the table. Lines longer than a message are truncated.
+func SplitMessage(content string, maxLength int) []string {
+	var messages []string
+	var lines []string // lines of the message being built
+	var length int     // characters of the message being built
+	var opener string  // first line of the code block the message ends in, if any
+	var afterBlock int // lines of the message up to the end of its last code block
+
+	send := func(lines []string) {
+		if message := strings.Join(lines, ""); strings.TrimSpace(message) != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		inCode := opener != ""
+		toggles := strings.Count(line, codeFence)%2 == 1
+
+		// Room left for the line, keeping room to close the code block if it's still open after it
+		room := func() int {
+			if inCode != toggles {
+				return maxLength - length - len(codeFence)
+			}
+			return maxLength - length
+		}
+
+		lineLength := utf8.RuneCountInString(line)
+		if lineLength > room() && afterBlock > 0 && afterBlock < len(lines) {
+			// Send the message up to its last code block, keeping the rest for the next one
+			send(lines[:afterBlock])
+			lines = append([]string(nil), lines[afterBlock:]...)
+			length = utf8.RuneCountInString(strings.Join(lines, ""))
+			afterBlock = 0
+		}
+		if lineLength > room() && len(lines) > 0 {
+			if inCode {
+				send(append(lines, codeFence))
+				lines, length = []string{opener}, utf8.RuneCountInString(opener)
+			} else {
+				send(lines)
+				lines, length = nil, 0
+			}
+			afterBlock = 0
+		}
+		if lineLength > room() {
+			line = TruncateText(line, room())
+			lineLength = utf8.RuneCountInString(line)
+		}
+
+		lines = append(lines, line)
+		length += lineLength
+
+		switch {
+		case toggles && !inCode:
+			opener = line
+		case toggles && inCode:
+			opener = ""
+			afterBlock = len(lines)
+		}
+	}
+	send(lines)
+
+	return messages
+}
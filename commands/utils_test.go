@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// checkMessages checks each message fits in maxLength and doesn't leave code blocks open
+func checkMessages(t *testing.T, messages []string, maxLength int) {
+	t.Helper()
+	for i, message := range messages {
+		if n := utf8.RuneCountInString(message); n > maxLength {
+			t.Errorf("message %d has %d characters, more than %d", i, n, maxLength)
+		}
+		if strings.Count(message, codeFence)%2 != 0 {
+			t.Errorf("message %d leaves a code block open:\n%s", i, message)
+		}
+	}
+}
+
+func table(header string, rows int) *TabularMessage {
+	var m TabularMessage
+	m.Header = header
+	m.SetTableHeader("Pos", "Driver", "Time")
+	for i := 1; i <= rows; i++ {
+		m.AddRow(fmt.Sprint(i), fmt.Sprintf("Driver Number %d", i), "1:31.447")
+	}
+	return &m
+}
+
+func TestSplitMessage(t *testing.T) {
+	short := table("Short", 3).String()
+	if messages := SplitMessage(short, MaxMessageLength); len(messages) != 1 || messages[0] != short {
+		t.Errorf("short message was split into %q", messages)
+	}
+
+	long := table("Long", 200).String()
+	messages := SplitMessage(long, MaxMessageLength)
+	if len(messages) < 2 {
+		t.Fatalf("message of %d characters was not split", utf8.RuneCountInString(long))
+	}
+	checkMessages(t, messages, MaxMessageLength)
+
+	// Every row is sent once, and every message has the header of the table
+	joined := strings.Join(messages, "")
+	for i := 1; i <= 200; i++ {
+		if n := strings.Count(joined, fmt.Sprintf("Driver Number %d ", i)); n != 1 {
+			t.Errorf("row %d was sent %d times", i, n)
+		}
+	}
+	for i, message := range messages {
+		if !strings.Contains(message, "```Pos") {
+			t.Errorf("message %d doesn't have the header of the table:\n%s", i, message)
+		}
+	}
+}
+
+func TestSplitMessageKeepsTablesWhole(t *testing.T) {
+	summary := table("Summary", 5).String()
+	content := summary + "\n" + table("Details", 100).String()
+
+	messages := SplitMessage(content, MaxMessageLength)
+	checkMessages(t, messages, MaxMessageLength)
+	if len(messages) < 2 || messages[0] != summary+"\n" {
+		t.Errorf("the summary wasn't sent in a message of its own:\n%s", messages[0])
+	}
+}
+
+func TestSplitMessageTruncatesLongLines(t *testing.T) {
+	messages := SplitMessage("first line\n"+strings.Repeat("a", 3000), 100)
+	checkMessages(t, messages, 100)
+	if len(messages) != 2 || messages[0] != "first line\n" {
+		t.Errorf("message with a long line was split into %q", messages)
+	}
+}
@@ -0,0 +1,159 @@
+package ergast
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// LiveTTL is the time replies about the current or next race weekend are cached for
+	LiveTTL = 5 * time.Minute
+	// SeasonTTL is the time replies about the current season are cached for
+	SeasonTTL = 30 * time.Minute
+	// HistoryTTL is the time replies spanning several seasons, like the results of a driver, are cached for
+	HistoryTTL = 6 * time.Hour
+	// ListTTL is the time lists of drivers, circuits, constructors and seasons are cached for
+	ListTTL = 24 * time.Hour
+	// EmptyTTL is the maximum time replies without records are cached for. These are often replies to
+	// requests with wrong arguments, like a round that doesn't exist, which aren't worth keeping.
+	EmptyTTL = 5 * time.Minute
+	// PermanentTTL is the time replies about completed seasons are cached for.
+	// Data of past seasons doesn't change, so they are effectively cached forever.
+	PermanentTTL = 100 * 365 * 24 * time.Hour
+)
+
+// CacheStats contains counters about the usage of a cache
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// Cache is an in-memory cache of replies from the API, indexed by endpoint.
+// It's safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	hits    uint64
+	misses  uint64
+	swept   time.Time
+	// TTL returns the time a reply for an endpoint is cached for
+	TTL func(endpoint string) time.Duration
+}
+
+type cacheEntry struct {
+	reply   MRReply
+	expires time.Time
+}
+
+// NewCache creates an empty cache using EndpointTTL to decide for how long replies are cached
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[string]cacheEntry),
+		TTL:     EndpointTTL,
+	}
+}
+
+// Get returns the cached reply for an endpoint, if there is one and it didn't expire yet
+func (c *Cache) Get(endpoint string) (MRReply, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[endpoint]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.entries, endpoint)
+		ok = false
+	}
+
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return MRReply{}, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	return entry.reply, true
+}
+
+// Set caches the reply for an endpoint for the time given by the TTL function of the cache.
+// Replies without records are cached for EmptyTTL at most.
+func (c *Cache) Set(endpoint string, reply MRReply) {
+	ttl := c.TTL(endpoint)
+	if isEmpty(reply) && ttl > EmptyTTL {
+		ttl = EmptyTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.entries[endpoint] = cacheEntry{
+		reply:   reply,
+		expires: now.Add(ttl),
+	}
+
+	// Entries are only removed when requested after expiring, so every now and then
+	// the expired entries that nobody requested again are removed as well
+	if now.Sub(c.swept) > EmptyTTL {
+		c.swept = now
+		for endpoint, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, endpoint)
+			}
+		}
+	}
+}
+
+// Stats returns the counters of the cache
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: entries,
+	}
+}
+
+// isEmpty tells if a reply has no records
+func isEmpty(reply MRReply) bool {
+	return reply.MRData.Total == "0"
+}
+
+// EndpointTTL returns the time the reply for an endpoint should be cached for,
+// based on how likely the data of the endpoint is to change.
+func EndpointTTL(endpoint string) time.Duration {
+	path := strings.TrimPrefix(endpoint, "/")
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		path = path[:i]
+	}
+	path = strings.TrimSuffix(path, ".json")
+	parts := strings.Split(path, "/")
+
+	if year, err := strconv.Atoi(parts[0]); err == nil {
+		if year < time.Now().Year() {
+			return PermanentTTL
+		}
+		return SeasonTTL
+	}
+
+	switch {
+	case parts[0] == "current" && len(parts) > 1 && (parts[1] == "next" || parts[1] == "last"):
+		return LiveTTL
+	case parts[0] == "current":
+		return SeasonTTL
+	case len(parts) == 1:
+		// Lists of drivers, circuits, constructors and seasons
+		return ListTTL
+	default:
+		// Replies not bound to a season, like all the results of a driver
+		return HistoryTTL
+	}
+}
@@ -0,0 +1,75 @@
+package ergast
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEndpointTTL(t *testing.T) {
+	year := time.Now().Year()
+
+	tests := []struct {
+		endpoint string
+		want     time.Duration
+	}{
+		{fmt.Sprintf("/%d/1/results.json?limit=1000&offset=0", year-1), PermanentTTL},
+		{"/1994/driverStandings.json", PermanentTTL},
+		{fmt.Sprintf("/%d/1/results.json", year), SeasonTTL},
+		{fmt.Sprintf("/%d.json", year), SeasonTTL},
+		{"/current/next.json", LiveTTL},
+		{"/current/last/results.json", LiveTTL},
+		{"/current.json?limit=1000&offset=0", SeasonTTL},
+		{"/current/driverStandings.json", SeasonTTL},
+		{"/drivers.json?limit=1000&offset=0", ListTTL},
+		{"/seasons.json", ListTTL},
+		{"/drivers/alonso/results.json?limit=1&offset=0", HistoryTTL},
+		{"/circuits/monza/results/1.json", HistoryTTL},
+	}
+
+	for _, tt := range tests {
+		if got := EndpointTTL(tt.endpoint); got != tt.want {
+			t.Errorf("EndpointTTL(%q) = %v, want %v", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	cache := NewCache()
+	ttl := time.Hour
+	cache.TTL = func(endpoint string) time.Duration { return ttl }
+
+	reply := MRReply{MRData{Total: "1"}}
+	cache.Set("/drivers.json", reply)
+	if _, ok := cache.Get("/drivers.json"); !ok {
+		t.Fatalf("reply not cached")
+	}
+
+	ttl = -time.Second
+	cache.Set("/seasons.json", reply)
+	if _, ok := cache.Get("/seasons.json"); ok {
+		t.Errorf("reply with a negative TTL was cached")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 1 hit, 1 miss and 1 entry", stats)
+	}
+}
+
+func TestCacheKeepsEmptyRepliesShortly(t *testing.T) {
+	cache := NewCache()
+
+	cache.Set("/1950/99/results.json", MRReply{MRData{Total: "0"}})
+	cache.Set("/1950/1/results.json", MRReply{MRData{Total: "20"}})
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if expires := cache.entries["/1950/99/results.json"].expires; time.Until(expires) > EmptyTTL {
+		t.Errorf("empty reply expires in %v, want at most %v", time.Until(expires), EmptyTTL)
+	}
+	if expires := cache.entries["/1950/1/results.json"].expires; time.Until(expires) <= EmptyTTL {
+		t.Errorf("reply with records expires in %v, want the permanent TTL", time.Until(expires))
+	}
+}
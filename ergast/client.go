@@ -1,9 +1,92 @@
 package ergast
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
 
-// BaseURL is the base url for the ergast api
-const BaseURL = "https://ergast.com/api/f1"
+// DefaultBaseURL is the base url for the ergast api
+const DefaultBaseURL = "https://ergast.com/api/f1"
 
-// Client is be the http client used for ergast http requests
-var Client http.Client
+// DefaultUserAgent is the user agent sent in the requests made by clients created with NewClient
+const DefaultUserAgent = "f1-discord-bot (+https://github.com/andrerfcsantos/f1-discord-bot)"
+
+const (
+	// DefaultTimeout is the timeout of each http request made by clients created with NewClient
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is the number of times clients created with NewClient retry a failed request
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the delay before the first retry of a failed request, doubling on each retry
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	// DefaultMaxRetryDelay is the maximum delay before retrying a failed request. When the API asks
+	// to retry later than this, the request fails right away.
+	DefaultMaxRetryDelay = 10 * time.Second
+)
+
+// Client makes requests to the ergast API, or to any other API compatible with it
+type Client struct {
+	// BaseURL is the base url of the API, without a trailing slash
+	BaseURL string
+	// HTTPClient is the http client used for the requests
+	HTTPClient *http.Client
+	// UserAgent is the value of the User-Agent header sent in the requests
+	UserAgent string
+	// Cache is where replies are cached. If nil, replies are not cached.
+	Cache *Cache
+	// DiskCache is where historical replies are stored. If nil, replies are not stored on disk.
+	DiskCache *DiskCache
+	// PageSize is the number of records requested in each page by APIGetAll
+	PageSize int
+	// RateLimiter keeps the requests within the rate limits of the API. If nil, requests are not limited.
+	RateLimiter *RateLimiter
+	// MaxRetries is the number of times a request is retried when the API is rate limiting or failing
+	MaxRetries int
+	// RetryBaseDelay is the delay before the first retry, doubling on each retry.
+	// A delay asked by the API through the Retry-After header takes precedence.
+	RetryBaseDelay time.Duration
+	// MaxRetryDelay is the maximum delay before a retry
+	MaxRetryDelay time.Duration
+
+	flights   flightGroup
+	fetches   uint64
+	coalesced uint64
+}
+
+// ClientStats contains counters about the requests made by a client
+type ClientStats struct {
+	// Fetches is the number of requests that reached the API, including retries
+	Fetches uint64
+	// Coalesced is the number of requests that shared the reply of an identical request in flight
+	Coalesced uint64
+}
+
+// NewClient creates a client for an ergast compatible API with the given base url,
+// caching replies in memory. If the base url is empty, DefaultBaseURL is used.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		UserAgent:  DefaultUserAgent,
+		Cache:      NewCache(),
+		PageSize:   DefaultPageSize,
+
+		RateLimiter:    NewRateLimiter(DefaultBurstLimit, DefaultHourlyLimit),
+		MaxRetries:     DefaultMaxRetries,
+		RetryBaseDelay: DefaultRetryBaseDelay,
+		MaxRetryDelay:  DefaultMaxRetryDelay,
+	}
+}
+
+// Stats returns the counters of the client
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Fetches:   atomic.LoadUint64(&c.fetches),
+		Coalesced: atomic.LoadUint64(&c.coalesced),
+	}
+}
@@ -0,0 +1,100 @@
+package ergast
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightTimeout is the maximum time a request shared by several callers can take,
+// retries included, since it doesn't run with the deadline of any of them
+const flightTimeout = 2 * time.Minute
+
+// flightGroup coalesces concurrent requests for the same endpoint, so only one of them
+// reaches the API and all share its reply. The zero value is ready to use.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a request in flight, shared by all callers asking for the same endpoint
+type flightCall struct {
+	done    chan struct{}
+	reply   MRReply
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do calls fn for the endpoint, unless there's already a call in flight for it, in which case
+// it waits for that call and returns its reply. The returned bool tells if the reply was shared.
+//
+// The call in flight doesn't run with the context of any of its callers, so a caller giving up,
+// like an autocomplete with a short timeout, doesn't fail the others. Each caller stops waiting
+// when its own context is done, and the call is canceled once every caller has stopped waiting.
+func (g *flightGroup) do(ctx context.Context, endpoint string, fn func(ctx context.Context) (MRReply, error)) (MRReply, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, shared := g.calls[endpoint]
+	if shared {
+		call.waiters++
+	} else {
+		callCtx, cancel := context.WithTimeout(detachedContext{parent: ctx}, flightTimeout)
+		call = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[endpoint] = call
+
+		go func() {
+			call.reply, call.err = fn(callCtx)
+			cancel()
+
+			g.mu.Lock()
+			if g.calls[endpoint] == call {
+				delete(g.calls, endpoint)
+			}
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.reply, call.err, shared
+	case <-ctx.Done():
+		g.leave(endpoint, call)
+		return MRReply{}, ctx.Err(), shared
+	}
+}
+
+// leave stops a caller from waiting for a call, canceling the call if nobody else is waiting for it.
+// An abandoned call is forgotten right away, so callers coming later don't share its cancellation.
+func (g *flightGroup) leave(endpoint string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+
+	call.cancel()
+	if g.calls[endpoint] == call {
+		delete(g.calls, endpoint)
+	}
+}
+
+// detachedContext is a context carrying the values of its parent, but never canceled along with it
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
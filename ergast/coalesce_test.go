@@ -0,0 +1,179 @@
+package ergast
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until a call for an endpoint has the given number of callers waiting for it
+func waitForWaiters(t *testing.T, g *flightGroup, endpoint string, waiters int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[endpoint]
+		n := 0
+		if ok {
+			n = call.waiters
+		}
+		g.mu.Unlock()
+
+		if n == waiters {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("the call for %s never had %d callers waiting", endpoint, waiters)
+}
+
+func TestFlightGroupSharesCalls(t *testing.T) {
+	var g flightGroup
+	var calls int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (MRReply, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return MRReply{MRData{Total: "1"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	var shared int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reply, err, isShared := g.do(context.Background(), "/drivers.json", fn)
+			if err != nil || reply.MRData.Total != "1" {
+				t.Errorf("do returned %+v and %v, want the reply of the call", reply, err)
+			}
+			if isShared {
+				atomic.AddInt32(&shared, 1)
+			}
+		}()
+	}
+
+	waitForWaiters(t, &g, "/drivers.json", 5)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fn was called %d times, want 1", calls)
+	}
+	if shared != 4 {
+		t.Errorf("%d callers shared the reply, want 4", shared)
+	}
+}
+
+func TestFlightGroupCallerGivingUpDoesntFailOthers(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	var callErr error
+
+	fn := func(ctx context.Context) (MRReply, error) {
+		select {
+		case <-release:
+			return MRReply{MRData{Total: "1"}}, nil
+		case <-ctx.Done():
+			callErr = ctx.Err()
+			return MRReply{}, ctx.Err()
+		}
+	}
+
+	// The first caller, like an autocomplete, gives up early
+	shortCtx, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() {
+		_, err, _ := g.do(shortCtx, "/drivers.json", fn)
+		firstDone <- err
+	}()
+	waitForWaiters(t, &g, "/drivers.json", 1)
+
+	secondDone := make(chan error)
+	go func() {
+		_, err, _ := g.do(context.Background(), "/drivers.json", fn)
+		secondDone <- err
+	}()
+	waitForWaiters(t, &g, "/drivers.json", 2)
+
+	cancel()
+	if err := <-firstDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("caller giving up got %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-secondDone; err != nil {
+		t.Errorf("caller still waiting got %v, want the reply of the call", err)
+	}
+	if callErr != nil {
+		t.Errorf("the call was canceled with %v while a caller was waiting for it", callErr)
+	}
+}
+
+func TestFlightGroupCancelsAbandonedCalls(t *testing.T) {
+	var g flightGroup
+	canceled := make(chan struct{})
+
+	fn := func(ctx context.Context) (MRReply, error) {
+		<-ctx.Done()
+		close(canceled)
+		return MRReply{}, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	for _, ctx := range []context.Context{ctx1, ctx2} {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			g.do(ctx, "/drivers.json", fn)
+		}(ctx)
+	}
+	waitForWaiters(t, &g, "/drivers.json", 2)
+
+	cancel1()
+	select {
+	case <-canceled:
+		t.Fatalf("the call was canceled while a caller was still waiting for it")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel2()
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatalf("the call was not canceled once every caller gave up")
+	}
+	wg.Wait()
+
+	// A new caller starts a new call, instead of sharing the canceled one
+	reply, err, shared := g.do(context.Background(), "/drivers.json", func(ctx context.Context) (MRReply, error) {
+		return MRReply{MRData{Total: "1"}}, nil
+	})
+	if err != nil || shared || reply.MRData.Total != "1" {
+		t.Errorf("new caller got %+v, %v and shared %v, want a new call", reply, err, shared)
+	}
+}
+
+func TestFlightGroupKeepsContextValues(t *testing.T) {
+	type key struct{}
+	var g flightGroup
+
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	g.do(ctx, "/drivers.json", func(ctx context.Context) (MRReply, error) {
+		if got := ctx.Value(key{}); got != "value" {
+			t.Errorf("value in the context of the call is %v, want the value of the caller", got)
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("the context of the call has no deadline")
+		}
+		return MRReply{}, nil
+	})
+}
@@ -0,0 +1,148 @@
+package ergast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DiskCache stores replies with historical data in a directory, so they survive restarts.
+// Each reply is stored as a JSON file named after its endpoint.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a disk cache on the given directory, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("creating cache directory '%s': %v", dir, err)
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+// IsHistorical tells if the data of an endpoint is historical and rarely changes, like the
+// results of a completed season or the list of drivers. Only historical replies with records are stored on disk.
+func IsHistorical(endpoint string) bool {
+	return EndpointTTL(endpoint) >= ListTTL
+}
+
+// Get returns the stored reply for an endpoint along with the time it was stored
+func (dc *DiskCache) Get(endpoint string) (MRReply, time.Time, bool) {
+	path := dc.path(endpoint)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return MRReply{}, time.Time{}, false
+	}
+
+	replyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return MRReply{}, time.Time{}, false
+	}
+
+	var res MRReply
+	err = json.Unmarshal(replyBytes, &res)
+	if err != nil {
+		return MRReply{}, time.Time{}, false
+	}
+
+	return res, info.ModTime(), true
+}
+
+// Set stores the reply for an endpoint
+func (dc *DiskCache) Set(endpoint string, reply MRReply) error {
+	replyBytes, err := json.Marshal(reply)
+	if err != nil {
+		return fmt.Errorf("marshaling reply to json: %v", err)
+	}
+
+	// Write to a temporary file first, so a reply is never read half written
+	tmp, err := ioutil.TempFile(dc.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(replyBytes)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing reply to disk: %v", err)
+	}
+
+	err = os.Rename(tmp.Name(), dc.path(endpoint))
+	if err != nil {
+		return fmt.Errorf("moving reply into the cache: %v", err)
+	}
+	return nil
+}
+
+func (dc *DiskCache) path(endpoint string) string {
+	return filepath.Join(dc.Dir, url.PathEscape(endpoint))
+}
+
+// WarmCache requests the historical data used by the bot, so it gets stored in the caches of the client.
+// This includes the lists of seasons, drivers, circuits and constructors and, for each completed season,
+// its races, winners and final standings. Failed requests don't stop the warm up.
+func (c *Client) WarmCache(ctx context.Context) error {
+	var failed, total int
+	var firstErr error
+
+	try := func(err error) {
+		total++
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	_, err := c.Drivers(ctx)
+	try(err)
+	_, err = c.Circuits(ctx)
+	try(err)
+	_, err = c.Constructors(ctx)
+	try(err)
+
+	seasonTable, err := c.Seasons(ctx)
+	try(err)
+
+	currentYear := time.Now().Year()
+	for _, season := range seasonTable.Seasons {
+		if ctx.Err() != nil {
+			return fmt.Errorf("warming up cache: %w", ctx.Err())
+		}
+
+		year, err := strconv.Atoi(season.Year)
+		if err != nil || year >= currentYear {
+			continue
+		}
+
+		_, err = c.RequestSeason(ctx, season.Year)
+		try(err)
+		_, err = c.RequestSeasonWinners(ctx, season.Year)
+		try(err)
+		_, err = c.RequestDriverStandings(ctx, season.Year)
+		try(err)
+		// The constructors championship only started in 1958, earlier seasons have no standings
+		_, err = c.RequestConstructorStandings(ctx, season.Year)
+		if !errors.Is(err, ErrNoStandings) {
+			try(err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed, first error: %v", failed, total, firstErr)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package ergast
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when the API replies that the requested endpoint doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned when the rate limits of the API don't allow any more requests for now
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamDown is returned when the API can't be reached or is failing
+	ErrUpstreamDown = errors.New("upstream down")
+)
+
+// APIError is an error from a request to the API. It wraps one of ErrNotFound, ErrRateLimited
+// or ErrUpstreamDown, so it can be checked with errors.Is.
+type APIError struct {
+	Endpoint string
+	// StatusCode of the reply, 0 if there was no reply
+	StatusCode int
+	// Kind is one of ErrNotFound, ErrRateLimited or ErrUpstreamDown
+	Kind error
+	// Err is the underlying error, if any
+	Err error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("GET %s: %v", e.Endpoint, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+// Is makes errors.Is match the kind of the error
+func (e *APIError) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
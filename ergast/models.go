@@ -38,6 +38,7 @@ type MRData struct {
 	DriverTable      DriverTable      `json:"DriverTable"`
 	ConstructorTable ConstructorTable `json:"ConstructorTable"`
 	SeasonTable      SeasonTable      `json:"SeasonTable"`
+	StandingsTable   StandingsTable   `json:"StandingsTable"`
 }
 
 // Season represents a f1 season
@@ -79,6 +80,27 @@ func (ct *CircuitTable) HasCircuit(circuitID string) bool {
 	return false
 }
 
+// FindCircuit returns the circuit with a given id present on the circuit table
+func (ct *CircuitTable) FindCircuit(circuitID string) (Circuit, bool) {
+	for _, circuit := range ct.Circuits {
+		if circuit.CircuitID == circuitID {
+			return circuit, true
+		}
+	}
+
+	return Circuit{}, false
+}
+
+// CircuitIDs returns the ids of the circuits on the circuit table
+func (ct *CircuitTable) CircuitIDs() []string {
+	ids := make([]string, 0, len(ct.Circuits))
+	for _, circuit := range ct.Circuits {
+		ids = append(ids, circuit.CircuitID)
+	}
+
+	return ids
+}
+
 // DriverTable contains a list of drivers
 type DriverTable struct {
 	Drivers []Driver `json:"Drivers"`
@@ -95,6 +117,27 @@ func (dt *DriverTable) HasDriver(driverID string) bool {
 	return false
 }
 
+// FindDriver returns the driver with a given id present on the driver table
+func (dt *DriverTable) FindDriver(driverID string) (Driver, bool) {
+	for _, driver := range dt.Drivers {
+		if driver.DriverID == driverID {
+			return driver, true
+		}
+	}
+
+	return Driver{}, false
+}
+
+// DriverIDs returns the ids of the drivers on the driver table
+func (dt *DriverTable) DriverIDs() []string {
+	ids := make([]string, 0, len(dt.Drivers))
+	for _, driver := range dt.Drivers {
+		ids = append(ids, driver.DriverID)
+	}
+
+	return ids
+}
+
 // ConstructorTable represents a list of constructors
 type ConstructorTable struct {
 	Constructors []Constructor `json:"Constructors"`
@@ -111,6 +154,27 @@ func (dt *ConstructorTable) HasConstructor(constructorID string) bool {
 	return false
 }
 
+// FindConstructor returns the constructor with a given id present on the constructor table
+func (dt *ConstructorTable) FindConstructor(constructorID string) (Constructor, bool) {
+	for _, constructor := range dt.Constructors {
+		if constructor.ConstructorID == constructorID {
+			return constructor, true
+		}
+	}
+
+	return Constructor{}, false
+}
+
+// ConstructorIDs returns the ids of the constructors on the constructor table
+func (dt *ConstructorTable) ConstructorIDs() []string {
+	ids := make([]string, 0, len(dt.Constructors))
+	for _, constructor := range dt.Constructors {
+		ids = append(ids, constructor.ConstructorID)
+	}
+
+	return ids
+}
+
 // RaceTable represents a list of races
 type RaceTable struct {
 	Season string `json:"season"`
@@ -118,6 +182,40 @@ type RaceTable struct {
 	Races  []Race `json:"Races"`
 }
 
+// StandingsTable represents a list of championship standings
+type StandingsTable struct {
+	Season         string          `json:"season"`
+	StandingsLists []StandingsList `json:"StandingsLists"`
+}
+
+// StandingsList represents the championship standings after a given round of a season.
+// Depending on the request, only one of DriverStandings or ConstructorStandings is filled.
+type StandingsList struct {
+	Season               string                `json:"season"`
+	Round                string                `json:"round"`
+	DriverStandings      []DriverStanding      `json:"DriverStandings"`
+	ConstructorStandings []ConstructorStanding `json:"ConstructorStandings"`
+}
+
+// DriverStanding represents the position of a driver in the drivers championship
+type DriverStanding struct {
+	Position     string        `json:"position"`
+	PositionText string        `json:"positionText"`
+	Points       string        `json:"points"`
+	Wins         string        `json:"wins"`
+	Driver       Driver        `json:"Driver"`
+	Constructors []Constructor `json:"Constructors"`
+}
+
+// ConstructorStanding represents the position of a constructor in the constructors championship
+type ConstructorStanding struct {
+	Position     string      `json:"position"`
+	PositionText string      `json:"positionText"`
+	Points       string      `json:"points"`
+	Wins         string      `json:"wins"`
+	Constructor  Constructor `json:"Constructor"`
+}
+
 // Location represents the location of a grand prix
 type Location struct {
 	Lat      string `json:"lat"`
@@ -142,6 +240,14 @@ type Race struct {
 	RaceName string       `json:"raceName"`
 	Circuit  Circuit      `json:"Circuit"`
 	Results  []RaceResult `json:"Results"`
+	// QualifyingResults is only filled on qualifying requests
+	QualifyingResults []QualifyingResult `json:"QualifyingResults"`
+	// SprintResults is only filled on sprint requests
+	SprintResults []RaceResult `json:"SprintResults"`
+	// Laps is only filled on lap times requests
+	Laps []Lap `json:"Laps"`
+	// PitStops is only filled on pit stops requests
+	PitStops []PitStop `json:"PitStops"`
 	DateTime
 	FirstPractice  *DateTime `json:"FirstPractice"`
 	SecondPractice *DateTime `json:"SecondPractice"`
@@ -165,6 +271,42 @@ type RaceResult struct {
 	FastestLap   FastestLap  `json:"FastestLap"`
 }
 
+// QualifyingResult represents the result of a driver in a qualifying session.
+// Q2 and Q3 are empty for drivers knocked out in earlier sessions.
+type QualifyingResult struct {
+	Number      string      `json:"number"`
+	Position    string      `json:"position"`
+	Driver      Driver      `json:"Driver"`
+	Constructor Constructor `json:"Constructor"`
+	Q1          string      `json:"Q1"`
+	Q2          string      `json:"Q2,omitempty"`
+	Q3          string      `json:"Q3,omitempty"`
+}
+
+// Lap represents the timings of the drivers in a lap of a race
+type Lap struct {
+	Number  string   `json:"number"`
+	Timings []Timing `json:"Timings"`
+}
+
+// Timing represents the time and position of a driver in a lap
+type Timing struct {
+	DriverID string `json:"driverId"`
+	Position string `json:"position"`
+	Time     string `json:"time"`
+}
+
+// PitStop represents a pit stop made by a driver during a race
+type PitStop struct {
+	DriverID string `json:"driverId"`
+	Lap      string `json:"lap"`
+	Stop     string `json:"stop"`
+	// Time of the day the stop was made
+	Time string `json:"time"`
+	// Duration is the time spent in the pit lane, in seconds
+	Duration string `json:"duration"`
+}
+
 type DateTime struct {
 	Date string `json:"date"`
 	Time string `json:"time"`
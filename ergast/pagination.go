@@ -0,0 +1,169 @@
+package ergast
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of records requested in each page by clients created with NewClient.
+// It's the maximum the ergast API allows, other APIs might cap it to a lower value.
+const DefaultPageSize = 1000
+
+// APIGetAll makes GET requests to all the pages of the specified API endpoint and merges them in a single reply.
+// The endpoint must not have the limit and offset parameters, as these are set for each page.
+// Pages are requested until the total of records of the reply is reached.
+func (c *Client) APIGetAll(ctx context.Context, endpoint string) (MRReply, error) {
+	return c.getPages(ctx, endpoint, 0)
+}
+
+// APIGetLast makes GET requests for the last n records of the specified API endpoint and merges them
+// in a single reply. The endpoint must not have the limit and offset parameters. The total of records
+// is requested first, so only the pages with the last records are requested.
+func (c *Client) APIGetLast(ctx context.Context, endpoint string, n int) (MRReply, error) {
+	reply, err := c.APIGet(ctx, pageEndpoint(endpoint, 1, 0))
+	if err != nil {
+		return MRReply{}, err
+	}
+
+	total, err := strconv.Atoi(reply.MRData.Total)
+	if err != nil {
+		return MRReply{}, fmt.Errorf("parsing total of records '%s': %v", reply.MRData.Total, err)
+	}
+
+	offset := total - n
+	if offset < 0 {
+		offset = 0
+	}
+	return c.getPages(ctx, endpoint, offset)
+}
+
+// getPages requests the pages of an endpoint from a given offset until the total of records is reached,
+// merging them in a single reply
+func (c *Client) getPages(ctx context.Context, endpoint string, start int) (MRReply, error) {
+	var res MRReply
+
+	total := start + 1
+	for offset := start; offset < total; {
+		page, err := c.APIGet(ctx, pageEndpoint(endpoint, c.PageSize, offset))
+		if err != nil {
+			return MRReply{}, err
+		}
+
+		total, err = strconv.Atoi(page.MRData.Total)
+		if err != nil {
+			return MRReply{}, fmt.Errorf("parsing total of records '%s': %v", page.MRData.Total, err)
+		}
+
+		// The API can return less records per page than requested, so the limit
+		// of the reply is used to know where the next page starts
+		limit, err := strconv.Atoi(page.MRData.Limit)
+		if err != nil {
+			return MRReply{}, fmt.Errorf("parsing limit of records '%s': %v", page.MRData.Limit, err)
+		}
+		if limit <= 0 {
+			return MRReply{}, fmt.Errorf("invalid limit of records %d for page at offset %d", limit, offset)
+		}
+
+		if offset == start {
+			res = page
+		} else {
+			res.MRData.merge(page.MRData)
+		}
+
+		offset += limit
+	}
+
+	limit := total - start
+	if limit < 0 {
+		limit = 0
+	}
+	res.MRData.Limit = strconv.Itoa(limit)
+	res.MRData.Offset = strconv.Itoa(start)
+
+	return res, nil
+}
+
+// pageEndpoint returns the endpoint for a page of records
+func pageEndpoint(endpoint string, limit, offset int) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%slimit=%d&offset=%d", endpoint, sep, limit, offset)
+}
+
+// merge appends the tables of another page to the tables of the reply.
+// Replies can be shared through the cache, so new slices are always created instead of
+// appending to the existing ones.
+func (d *MRData) merge(page MRData) {
+	d.RaceTable.Races = mergeRaces(d.RaceTable.Races, page.RaceTable.Races)
+	d.CircuitTable.Circuits = concat(d.CircuitTable.Circuits, page.CircuitTable.Circuits)
+	d.DriverTable.Drivers = concat(d.DriverTable.Drivers, page.DriverTable.Drivers)
+	d.ConstructorTable.Constructors = concat(d.ConstructorTable.Constructors, page.ConstructorTable.Constructors)
+	d.SeasonTable.Seasons = concat(d.SeasonTable.Seasons, page.SeasonTable.Seasons)
+	d.StandingsTable.StandingsLists = mergeStandings(d.StandingsTable.StandingsLists, page.StandingsTable.StandingsLists)
+}
+
+// mergeRaces appends the races of a page to a list of races. Records are paginated
+// individually, so the first race of the page can be the continuation of the last race of the list.
+func mergeRaces(races, page []Race) []Race {
+	if len(races) == 0 || len(page) == 0 {
+		return concat(races, page)
+	}
+
+	last, first := races[len(races)-1], page[0]
+	if last.Season != first.Season || last.Round != first.Round {
+		return concat(races, page)
+	}
+
+	last.Results = concat(last.Results, first.Results)
+	last.QualifyingResults = concat(last.QualifyingResults, first.QualifyingResults)
+	last.SprintResults = concat(last.SprintResults, first.SprintResults)
+	last.PitStops = concat(last.PitStops, first.PitStops)
+	last.Laps = mergeLaps(last.Laps, first.Laps)
+
+	merged := concat(races[:len(races)-1], []Race{last})
+	return concat(merged, page[1:])
+}
+
+// mergeLaps appends the laps of a page to a list of laps, merging the timings
+// of a lap split between the two
+func mergeLaps(laps, page []Lap) []Lap {
+	if len(laps) == 0 || len(page) == 0 || laps[len(laps)-1].Number != page[0].Number {
+		return concat(laps, page)
+	}
+
+	last := laps[len(laps)-1]
+	last.Timings = concat(last.Timings, page[0].Timings)
+
+	merged := concat(laps[:len(laps)-1], []Lap{last})
+	return concat(merged, page[1:])
+}
+
+// mergeStandings appends the standings lists of a page to a list of standings lists, merging
+// the standings of a list split between the two
+func mergeStandings(lists, page []StandingsList) []StandingsList {
+	if len(lists) == 0 || len(page) == 0 {
+		return concat(lists, page)
+	}
+
+	last, first := lists[len(lists)-1], page[0]
+	if last.Season != first.Season || last.Round != first.Round {
+		return concat(lists, page)
+	}
+
+	last.DriverStandings = concat(last.DriverStandings, first.DriverStandings)
+	last.ConstructorStandings = concat(last.ConstructorStandings, first.ConstructorStandings)
+
+	merged := concat(lists[:len(lists)-1], []StandingsList{last})
+	return concat(merged, page[1:])
+}
+
+// concat returns a new slice with the elements of a followed by the elements of b
+func concat[T any](a, b []T) []T {
+	res := make([]T, 0, len(a)+len(b))
+	res = append(res, a...)
+	return append(res, b...)
+}
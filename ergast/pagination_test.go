@@ -0,0 +1,183 @@
+package ergast
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves a list of results the way the API does, grouping the records
+// of each page by race and capping the records per page to maxLimit
+type pagedServer struct {
+	*httptest.Server
+	maxLimit int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// record is a result of a race listed by a pagedServer
+type record struct {
+	round  int
+	result RaceResult
+}
+
+func newPagedServer(t *testing.T, records []record, maxLimit int) *pagedServer {
+	s := &pagedServer{maxLimit: maxLimit}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL.RequestURI())
+		s.mu.Unlock()
+
+		limit, offset := 30, 0
+		if v := r.URL.Query().Get("limit"); v != "" {
+			limit, _ = strconv.Atoi(v)
+		}
+		if v := r.URL.Query().Get("offset"); v != "" {
+			offset, _ = strconv.Atoi(v)
+		}
+		if limit > s.maxLimit {
+			limit = s.maxLimit
+		}
+
+		var reply MRReply
+		reply.MRData.Limit = strconv.Itoa(limit)
+		reply.MRData.Offset = strconv.Itoa(offset)
+		reply.MRData.Total = strconv.Itoa(len(records))
+
+		for i := offset; i < offset+limit && i < len(records); i++ {
+			races := reply.MRData.RaceTable.Races
+			round := strconv.Itoa(records[i].round)
+			if len(races) == 0 || races[len(races)-1].Round != round {
+				races = append(races, Race{Season: "2020", Round: round})
+			}
+			races[len(races)-1].Results = append(races[len(races)-1].Results, records[i].result)
+			reply.MRData.RaceTable.Races = races
+		}
+
+		err := json.NewEncoder(w).Encode(reply)
+		if err != nil {
+			t.Errorf("encoding reply: %v", err)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *pagedServer) client() *Client {
+	client := NewClient(s.URL)
+	client.Cache = nil
+	client.RateLimiter = nil
+	return client
+}
+
+// raceRecords returns the records of races with the given number of results each
+func raceRecords(races, resultsPerRace int) []record {
+	var records []record
+	for round := 1; round <= races; round++ {
+		for position := 1; position <= resultsPerRace; position++ {
+			records = append(records, record{round: round, result: RaceResult{Position: strconv.Itoa(position)}})
+		}
+	}
+	return records
+}
+
+// checkRaces checks the rounds of a list of races and their number of results
+func checkRaces(t *testing.T, races []Race, firstRound, lastRound, resultsPerRace int) {
+	t.Helper()
+
+	if len(races) != lastRound-firstRound+1 {
+		t.Fatalf("got %d races, want rounds %d to %d", len(races), firstRound, lastRound)
+	}
+	for i, race := range races {
+		if want := strconv.Itoa(firstRound + i); race.Round != want {
+			t.Errorf("race %d is round %s, want %s", i, race.Round, want)
+		}
+		if len(race.Results) != resultsPerRace {
+			t.Errorf("round %s has %d results, want %d", race.Round, len(race.Results), resultsPerRace)
+		}
+	}
+}
+
+func TestAPIGetAllMergesPages(t *testing.T) {
+	// Pages of 7 records split most races of 3 results between two pages
+	server := newPagedServer(t, raceRecords(10, 3), 7)
+
+	reply, err := server.client().APIGetAll(context.Background(), "/constructors/ferrari/results.json")
+	if err != nil {
+		t.Fatalf("APIGetAll: %v", err)
+	}
+
+	checkRaces(t, reply.MRData.RaceTable.Races, 1, 10, 3)
+	if len(server.requests) != 5 {
+		t.Errorf("made %d requests, want 5 pages of 7 records: %v", len(server.requests), server.requests)
+	}
+	if reply.MRData.Limit != "30" || reply.MRData.Offset != "0" || reply.MRData.Total != "30" {
+		t.Errorf("limit, offset and total are %s, %s and %s, want 30, 0 and 30", reply.MRData.Limit, reply.MRData.Offset, reply.MRData.Total)
+	}
+}
+
+func TestMergeDoesNotModifySharedPages(t *testing.T) {
+	first := []Race{{Season: "2020", Round: "1", Results: []RaceResult{{Position: "1"}}}}
+	page := []Race{{Season: "2020", Round: "1", Results: []RaceResult{{Position: "2"}}}, {Season: "2020", Round: "2"}}
+
+	merged := mergeRaces(first, page)
+
+	if len(merged) != 2 || len(merged[0].Results) != 2 {
+		t.Fatalf("merged races = %+v, want round 1 with 2 results and round 2", merged)
+	}
+	if len(first[0].Results) != 1 || len(page[0].Results) != 1 {
+		t.Errorf("merging modified the pages")
+	}
+}
+
+func TestAPIGetLastRequestsOnlyTheLastPages(t *testing.T) {
+	server := newPagedServer(t, raceRecords(100, 1), 1000)
+
+	reply, err := server.client().APIGetLast(context.Background(), "/drivers/alonso/results.json", 10)
+	if err != nil {
+		t.Fatalf("APIGetLast: %v", err)
+	}
+
+	checkRaces(t, reply.MRData.RaceTable.Races, 91, 100, 1)
+
+	want := []string{
+		"/drivers/alonso/results.json?limit=1&offset=0",
+		"/drivers/alonso/results.json?limit=1000&offset=90",
+	}
+	if len(server.requests) != len(want) || server.requests[0] != want[0] || server.requests[1] != want[1] {
+		t.Errorf("requests = %v, want %v", server.requests, want)
+	}
+}
+
+func TestRequestConstructorLastResults(t *testing.T) {
+	tests := []struct {
+		name           string
+		races          int
+		resultsPerRace int
+		n              int
+		firstRound     int
+	}{
+		{"two cars per race", 50, 2, 10, 41},
+		{"more cars than guessed", 50, 4, 10, 41},
+		{"less races than asked", 3, 2, 10, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPagedServer(t, raceRecords(tt.races, tt.resultsPerRace), 1000)
+
+			table, err := server.client().RequestConstructorLastResults(context.Background(), "ferrari", tt.n)
+			if err != nil {
+				t.Fatalf("RequestConstructorLastResults: %v", err)
+			}
+
+			// The first race requested can have only part of its results, it must be left out
+			checkRaces(t, table.Races, tt.firstRound, tt.races, tt.resultsPerRace)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package ergast
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBurstLimit is the number of requests per second allowed by the ergast API
+	DefaultBurstLimit = 4
+	// DefaultHourlyLimit is the number of requests per hour allowed by the ergast API
+	DefaultHourlyLimit = 200
+	// DefaultMaxRateLimitWait is the maximum time a request waits for the rate limiter before giving up
+	DefaultMaxRateLimitWait = 10 * time.Second
+)
+
+// RateLimiter limits the requests made to the API to a number per second (the burst limit)
+// and to a number per hour. It's safe for concurrent use.
+type RateLimiter struct {
+	// MaxWait is the maximum time Wait blocks. Requests that would have to wait
+	// longer fail right away with ErrRateLimited.
+	MaxWait time.Duration
+
+	mu      sync.Mutex
+	buckets []*tokenBucket
+}
+
+// tokenBucket allows up to capacity requests at once, refilling at rate requests per second.
+// Tokens go below zero when requests are reserved in advance.
+type tokenBucket struct {
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing burst requests per second and hourly requests per hour
+func NewRateLimiter(burst, hourly int) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		MaxWait: DefaultMaxRateLimitWait,
+		buckets: []*tokenBucket{
+			{capacity: float64(burst), rate: float64(burst), tokens: float64(burst), last: now},
+			{capacity: float64(hourly), rate: float64(hourly) / 3600, tokens: float64(hourly), last: now},
+		},
+	}
+}
+
+// Wait blocks until a request can be made within the limits. If that takes longer than MaxWait,
+// or longer than the deadline of the context, it returns ErrRateLimited without waiting.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	rl.mu.Lock()
+
+	now := time.Now()
+	var wait time.Duration
+	for _, b := range rl.buckets {
+		b.refill(now)
+		if d := b.waitFor(); d > wait {
+			wait = d
+		}
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	if wait > rl.MaxWait || (hasDeadline && now.Add(wait).After(deadline)) {
+		rl.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	// Reserve the request right away, so concurrent requests queue after this one
+	for _, b := range rl.buckets {
+		b.tokens--
+	}
+	rl.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+// waitFor returns the time until the bucket has a token available
+func (b *tokenBucket) waitFor() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -0,0 +1,75 @@
+package ergast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurst(t *testing.T) {
+	rl := NewRateLimiter(3, 100)
+	rl.MaxWait = 0
+
+	for i := 0; i < 3; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("request %d of the burst: %v", i+1, err)
+		}
+	}
+
+	if err := rl.Wait(context.Background()); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("request after the burst returned %v, want ErrRateLimited", err)
+	}
+}
+
+func TestRateLimiterWaitsForTokens(t *testing.T) {
+	rl := NewRateLimiter(20, 100)
+	for i := 0; i < 20; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("request %d of the burst: %v", i+1, err)
+		}
+	}
+
+	// A token is refilled every 50ms
+	start := time.Now()
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("request after the burst: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("request after the burst waited %v, want about 50ms", elapsed)
+	}
+}
+
+func TestRateLimiterHourlyLimit(t *testing.T) {
+	rl := NewRateLimiter(10, 2)
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("request %d within the hourly limit: %v", i+1, err)
+		}
+	}
+
+	// The next token of the hourly bucket takes 30 minutes, way more than MaxWait
+	if err := rl.Wait(context.Background()); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("request over the hourly limit returned %v, want ErrRateLimited", err)
+	}
+}
+
+func TestRateLimiterHonoursDeadline(t *testing.T) {
+	rl := NewRateLimiter(1, 100)
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	// The next token takes a second, which is after the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := rl.Wait(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("request that can't be made before the deadline returned %v, want ErrRateLimited", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("request waited %v before failing, want it to fail right away", elapsed)
+	}
+}
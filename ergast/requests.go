@@ -1,78 +1,370 @@
 package ergast
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// ErrNoRaces is returned when a request succeeds but the reply has no races
+var ErrNoRaces = errors.New("request ok, but no races returned")
+
+// ErrNoStandings is returned when a request succeeds but the reply has no standings
+var ErrNoStandings = errors.New("request ok, but no standings returned")
+
 // RequestNextRace uses the ergast api to request information the next race
-func RequestNextRace() (Race, error) {
-	reply, err := APIGet("/current/next.json")
+func (c *Client) RequestNextRace(ctx context.Context) (Race, error) {
+	reply, err := c.APIGet(ctx, "/current/next.json")
 	if err != nil {
 		return Race{}, err
 	}
 
 	if len(reply.MRData.RaceTable.Races) == 0 {
-		return Race{}, fmt.Errorf("request ok, but no races returned")
+		return Race{}, ErrNoRaces
 	}
 	return reply.MRData.RaceTable.Races[0], nil
 }
 
 // RequestLastRace requests information about the last race
-func RequestLastRace() (Race, error) {
-	reply, err := APIGet("/current/last/results.json")
+func (c *Client) RequestLastRace(ctx context.Context) (Race, error) {
+	reply, err := c.APIGet(ctx, "/current/last/results.json")
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestRaceResults requests the results of a given round of a season
+func (c *Client) RequestRaceResults(ctx context.Context, season, round string) (Race, error) {
+	endpoint := fmt.Sprintf("/%s/%s/results.json", pathArg(season), pathArg(round))
+	reply, err := c.APIGetAll(ctx, endpoint)
 	if err != nil {
 		return Race{}, err
 	}
 	if len(reply.MRData.RaceTable.Races) == 0 {
-		return Race{}, fmt.Errorf("request ok, but no races returned")
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestLastQualifying requests the qualifying results of the last race
+func (c *Client) RequestLastQualifying(ctx context.Context) (Race, error) {
+	reply, err := c.APIGet(ctx, "/current/last/qualifying.json")
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestQualifying requests the qualifying results of a given round of a season
+func (c *Client) RequestQualifying(ctx context.Context, season, round string) (Race, error) {
+	endpoint := fmt.Sprintf("/%s/%s/qualifying.json", pathArg(season), pathArg(round))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestLastSprint requests the sprint results of the last race weekend
+func (c *Client) RequestLastSprint(ctx context.Context) (Race, error) {
+	reply, err := c.APIGet(ctx, "/current/last/sprint.json")
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestSprint requests the sprint results of a given round of a season
+func (c *Client) RequestSprint(ctx context.Context, season, round string) (Race, error) {
+	endpoint := fmt.Sprintf("/%s/%s/sprint.json", pathArg(season), pathArg(round))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
 	}
 	return reply.MRData.RaceTable.Races[0], nil
 }
 
 // RequestCircuitResults requests information about results on a given circuit in the last years
-func RequestCircuitResults(circuitID string) (RaceTable, error) {
-	endpoint := fmt.Sprintf("/circuits/%s/results/1.json?limit=1000", strings.ToLower(circuitID))
-	reply, err := APIGet(endpoint)
+func (c *Client) RequestCircuitResults(ctx context.Context, circuitID string) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/circuits/%s/results/1.json", pathArg(circuitID))
+	reply, err := c.APIGetAll(ctx, endpoint)
 	if err != nil {
 		return RaceTable{}, err
 	}
 	if len(reply.MRData.RaceTable.Races) == 0 {
-		return RaceTable{}, fmt.Errorf("request ok, but no races returned")
+		return RaceTable{}, ErrNoRaces
 	}
 	return reply.MRData.RaceTable, nil
 }
 
-// RequestDriverResults requests information about results for a given driver in the last races
-func RequestDriverResults(driverID string) (RaceTable, error) {
-	endpoint := fmt.Sprintf("/drivers/%s/results.json?limit=1000", strings.ToLower(driverID))
-	reply, err := APIGet(endpoint)
+// RequestDriverResults requests the results of a given driver in all their races
+func (c *Client) RequestDriverResults(ctx context.Context, driverID string) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/drivers/%s/results.json", pathArg(driverID))
+	reply, err := c.APIGetAll(ctx, endpoint)
 	if err != nil {
 		return RaceTable{}, err
 	}
 	if len(reply.MRData.RaceTable.Races) == 0 {
-		return RaceTable{}, fmt.Errorf("request ok, but no races returned")
+		return RaceTable{}, ErrNoRaces
 	}
 	return reply.MRData.RaceTable, nil
 }
 
+// RequestDriverLastResults requests the results of a given driver in their last n races
+func (c *Client) RequestDriverLastResults(ctx context.Context, driverID string, n int) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/drivers/%s/results.json", pathArg(driverID))
+	return c.requestLastRaces(ctx, endpoint, n, 1)
+}
+
+// FirstQualifyingSeason is the first season with qualifying results in the API
+const FirstQualifyingSeason = 1994
+
+// RequestDriverPoleCount requests the number of times a given driver qualified in first place.
+// The API only has qualifying results since FirstQualifyingSeason, so earlier poles are not counted.
+func (c *Client) RequestDriverPoleCount(ctx context.Context, driverID string) (int, error) {
+	endpoint := fmt.Sprintf("/drivers/%s/qualifying/1.json?limit=1", pathArg(driverID))
+	reply, err := c.APIGet(ctx, endpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := strconv.Atoi(reply.MRData.Total)
+	if err != nil {
+		return 0, fmt.Errorf("parsing total of qualifying results '%s': %v", reply.MRData.Total, err)
+	}
+	return total, nil
+}
+
+// RequestLaps requests the lap times of a driver in a given round of a season
+func (c *Client) RequestLaps(ctx context.Context, season, round, driverID string) (Race, error) {
+	endpoint := fmt.Sprintf("/%s/%s/drivers/%s/laps.json", pathArg(season), pathArg(round), pathArg(driverID))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestPitStops requests the pit stops made in a given round of a season
+func (c *Client) RequestPitStops(ctx context.Context, season, round string) (Race, error) {
+	endpoint := fmt.Sprintf("/%s/%s/pitstops.json", pathArg(season), pathArg(round))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return Race{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return Race{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable.Races[0], nil
+}
+
+// RequestConstructorLastResults requests the results of a given constructor in its last n races.
+// Constructors can have a long history, so only the records of the last races are requested.
+func (c *Client) RequestConstructorLastResults(ctx context.Context, constructorID string, n int) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/constructors/%s/results.json", pathArg(constructorID))
+	return c.requestLastRaces(ctx, endpoint, n, 2)
+}
+
+// requestLastRaces requests the last n races of an endpoint listing results, guessing how many results
+// each race has. Records are paginated individually, so the first race of the records requested can
+// have only part of its results. More records are requested until there are more than n races,
+// so that race can be left out, or until there are no more records.
+func (c *Client) requestLastRaces(ctx context.Context, endpoint string, n, resultsPerRace int) (RaceTable, error) {
+	for records := (n + 1) * resultsPerRace; ; records *= 2 {
+		reply, err := c.APIGetLast(ctx, endpoint, records)
+		if err != nil {
+			return RaceTable{}, err
+		}
+
+		table := reply.MRData.RaceTable
+		if len(table.Races) == 0 {
+			return RaceTable{}, ErrNoRaces
+		}
+		if len(table.Races) > n {
+			table.Races = table.Races[len(table.Races)-n:]
+			return table, nil
+		}
+		if reply.MRData.Offset == "0" {
+			return table, nil
+		}
+	}
+}
+
+// RequestConstructorSeasons requests the seasons in which a given constructor took part
+func (c *Client) RequestConstructorSeasons(ctx context.Context, constructorID string) (SeasonTable, error) {
+	endpoint := fmt.Sprintf("/constructors/%s/seasons.json", pathArg(constructorID))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return SeasonTable{}, err
+	}
+	if len(reply.MRData.SeasonTable.Seasons) == 0 {
+		return SeasonTable{}, fmt.Errorf("empty list of seasons from ergast")
+	}
+	return reply.MRData.SeasonTable, nil
+}
+
+// RequestConstructorDrivers requests the drivers that raced for a given constructor
+func (c *Client) RequestConstructorDrivers(ctx context.Context, constructorID string) (DriverTable, error) {
+	endpoint := fmt.Sprintf("/constructors/%s/drivers.json", pathArg(constructorID))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return DriverTable{}, err
+	}
+	if len(reply.MRData.DriverTable.Drivers) == 0 {
+		return DriverTable{}, fmt.Errorf("empty list of drivers from ergast")
+	}
+	return reply.MRData.DriverTable, nil
+}
+
+// RequestConstructorChampionships requests the final standings of the seasons in which
+// a given constructor won the constructors championship. The table is empty if the constructor
+// never won the championship.
+func (c *Client) RequestConstructorChampionships(ctx context.Context, constructorID string) (StandingsTable, error) {
+	endpoint := fmt.Sprintf("/constructors/%s/constructorStandings/1.json", pathArg(constructorID))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return StandingsTable{}, err
+	}
+
+	// The standings of the season being held are also returned when the constructor leads it,
+	// but the title is not decided until its last round
+	standings := reply.MRData.StandingsTable
+	if n := len(standings.StandingsLists); n > 0 {
+		last := standings.StandingsLists[n-1]
+		finished, err := c.seasonFinished(ctx, last.Season, last.Round)
+		if err != nil {
+			return StandingsTable{}, err
+		}
+		if !finished {
+			standings.StandingsLists = standings.StandingsLists[:n-1]
+		}
+	}
+	return standings, nil
+}
+
+// seasonFinished checks if a given round is the last one of a season
+func (c *Client) seasonFinished(ctx context.Context, season, round string) (bool, error) {
+	rt, err := c.RequestSeason(ctx, season)
+	if err != nil {
+		return false, err
+	}
+	return rt.Races[len(rt.Races)-1].Round == round, nil
+}
+
+// RequestConstructorPositionCount requests the number of times the cars of a given
+// constructor finished a race in a given position
+func (c *Client) RequestConstructorPositionCount(ctx context.Context, constructorID string, position string) (int, error) {
+	endpoint := fmt.Sprintf("/constructors/%s/results/%s.json?limit=1", pathArg(constructorID), pathArg(position))
+	reply, err := c.APIGet(ctx, endpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := strconv.Atoi(reply.MRData.Total)
+	if err != nil {
+		return 0, fmt.Errorf("parsing total of results '%s': %v", reply.MRData.Total, err)
+	}
+	return total, nil
+}
+
 // CurrentSeason requests information about races of the current season
-func CurrentSeason() (RaceTable, error) {
-	reply, err := APIGet("/current.json?limit=1000")
+func (c *Client) CurrentSeason(ctx context.Context) (RaceTable, error) {
+	reply, err := c.APIGetAll(ctx, "/current.json")
+	if err != nil {
+		return RaceTable{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return RaceTable{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable, nil
+}
+
+// RequestDriverStandings requests the drivers championship standings for a given season.
+// The season can be a year or "current".
+func (c *Client) RequestDriverStandings(ctx context.Context, season string) (StandingsList, error) {
+	endpoint := fmt.Sprintf("/%s/driverStandings.json", pathArg(season))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return StandingsList{}, err
+	}
+	if len(reply.MRData.StandingsTable.StandingsLists) == 0 {
+		return StandingsList{}, ErrNoStandings
+	}
+	return reply.MRData.StandingsTable.StandingsLists[0], nil
+}
+
+// RequestConstructorStandings requests the constructors championship standings for a given season.
+// The season can be a year or "current".
+func (c *Client) RequestConstructorStandings(ctx context.Context, season string) (StandingsList, error) {
+	endpoint := fmt.Sprintf("/%s/constructorStandings.json", pathArg(season))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return StandingsList{}, err
+	}
+	if len(reply.MRData.StandingsTable.StandingsLists) == 0 {
+		return StandingsList{}, ErrNoStandings
+	}
+	return reply.MRData.StandingsTable.StandingsLists[0], nil
+}
+
+// RequestSeason requests information about races of a given season.
+// The season can be a year or "current".
+func (c *Client) RequestSeason(ctx context.Context, season string) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/%s.json", pathArg(season))
+	reply, err := c.APIGetAll(ctx, endpoint)
+	if err != nil {
+		return RaceTable{}, err
+	}
+	if len(reply.MRData.RaceTable.Races) == 0 {
+		return RaceTable{}, ErrNoRaces
+	}
+	return reply.MRData.RaceTable, nil
+}
+
+// RequestSeasonWinners requests the winners of all the races already held in a given season.
+// Each race of the table only has the result of the winner.
+func (c *Client) RequestSeasonWinners(ctx context.Context, season string) (RaceTable, error) {
+	endpoint := fmt.Sprintf("/%s/results/1.json", pathArg(season))
+	reply, err := c.APIGetAll(ctx, endpoint)
 	if err != nil {
 		return RaceTable{}, err
 	}
 	if len(reply.MRData.RaceTable.Races) == 0 {
-		return RaceTable{}, fmt.Errorf("request ok, but no races returned")
+		return RaceTable{}, ErrNoRaces
 	}
 	return reply.MRData.RaceTable, nil
 }
 
 // Circuits requests a list of circuits
-func Circuits() (CircuitTable, error) {
-	reply, err := APIGet("/circuits.json?limit=1000")
+func (c *Client) Circuits(ctx context.Context) (CircuitTable, error) {
+	reply, err := c.APIGetAll(ctx, "/circuits.json")
 	if err != nil {
 		return CircuitTable{}, err
 	}
@@ -83,8 +375,8 @@ func Circuits() (CircuitTable, error) {
 }
 
 // Drivers requests a list of drivers
-func Drivers() (DriverTable, error) {
-	reply, err := APIGet("/drivers.json?limit=1000")
+func (c *Client) Drivers(ctx context.Context) (DriverTable, error) {
+	reply, err := c.APIGetAll(ctx, "/drivers.json")
 	if err != nil {
 		return DriverTable{}, err
 	}
@@ -95,8 +387,8 @@ func Drivers() (DriverTable, error) {
 }
 
 // Constructors requests a list of constructors
-func Constructors() (ConstructorTable, error) {
-	reply, err := APIGet("/constructors.json?limit=1000")
+func (c *Client) Constructors(ctx context.Context) (ConstructorTable, error) {
+	reply, err := c.APIGetAll(ctx, "/constructors.json")
 	if err != nil {
 		return ConstructorTable{}, err
 	}
@@ -107,8 +399,8 @@ func Constructors() (ConstructorTable, error) {
 }
 
 // Seasons requests a list of seasons
-func Seasons() (SeasonTable, error) {
-	reply, err := APIGet("/seasons.json?limit=1000")
+func (c *Client) Seasons(ctx context.Context) (SeasonTable, error) {
+	reply, err := c.APIGetAll(ctx, "/seasons.json")
 	if err != nil {
 		return SeasonTable{}, err
 	}
@@ -118,26 +410,178 @@ func Seasons() (SeasonTable, error) {
 	return reply.MRData.SeasonTable, nil
 }
 
+// pathArg prepares an argument given by the user, like the id of a driver, to be part of the path of an endpoint
+func pathArg(arg string) string {
+	return url.PathEscape(strings.ToLower(arg))
+}
+
 // APIGet makes a GET request to the specified API endpoint.
-func APIGet(endpoint string) (MRReply, error) {
+// Replies are served from the caches of the client when possible. Historical data stored
+// on disk is also served when the request to the API fails, even if outdated.
+// Concurrent requests for the same endpoint are coalesced into a single request to the API,
+// which keeps going as long as any of the callers waits for it.
+func (c *Client) APIGet(ctx context.Context, endpoint string) (MRReply, error) {
+	if c.Cache != nil {
+		if res, ok := c.Cache.Get(endpoint); ok {
+			return res, nil
+		}
+	}
+
+	res, err, shared := c.flights.do(ctx, endpoint, func(ctx context.Context) (MRReply, error) {
+		return c.getUncached(ctx, endpoint)
+	})
+	if shared {
+		atomic.AddUint64(&c.coalesced, 1)
+	}
+
+	return res, err
+}
+
+// getUncached gets the reply for an endpoint from the disk cache or from the API,
+// storing it in the caches of the client
+func (c *Client) getUncached(ctx context.Context, endpoint string) (MRReply, error) {
+	onDisk := c.DiskCache != nil && IsHistorical(endpoint)
+	if onDisk {
+		if res, storedAt, ok := c.DiskCache.Get(endpoint); ok && time.Since(storedAt) < EndpointTTL(endpoint) {
+			if c.Cache != nil {
+				c.Cache.Set(endpoint, res)
+			}
+			return res, nil
+		}
+	}
+
+	res, err := c.fetch(ctx, endpoint)
+	if err != nil {
+		if onDisk {
+			if res, _, ok := c.DiskCache.Get(endpoint); ok {
+				return res, nil
+			}
+		}
+		return MRReply{}, err
+	}
+
+	if c.Cache != nil {
+		c.Cache.Set(endpoint, res)
+	}
+	if onDisk && !isEmpty(res) {
+		// Failing to store the reply doesn't make it invalid, so the error is ignored
+		_ = c.DiskCache.Set(endpoint, res)
+	}
+
+	return res, nil
+}
+
+// fetch requests an endpoint to the API, without going through the caches.
+// Requests failing because the API is rate limiting or failing are retried with exponential backoff.
+func (c *Client) fetch(ctx context.Context, endpoint string) (MRReply, error) {
+	delay := c.RetryBaseDelay
+
+	for attempt := 0; ; attempt++ {
+		res, retryAfter, err := c.fetchOnce(ctx, endpoint)
+
+		// Requests stopped by the client side rate limiter are not retried, since the
+		// rate limiter already waited as much as it could
+		var apiErr *APIError
+		retriable := errors.As(err, &apiErr) &&
+			(apiErr.Kind == ErrUpstreamDown || (apiErr.Kind == ErrRateLimited && apiErr.StatusCode != 0))
+		if err == nil || !retriable || attempt >= c.MaxRetries {
+			return res, err
+		}
+
+		if retryAfter == 0 {
+			retryAfter = delay
+			delay *= 2
+		}
+		if retryAfter > c.MaxRetryDelay {
+			return res, err
+		}
+
+		select {
+		case <-time.After(retryAfter):
+		case <-ctx.Done():
+			return MRReply{}, fmt.Errorf("GET %s: %w", endpoint, ctx.Err())
+		}
+	}
+}
+
+// fetchOnce makes a single request to an endpoint of the API.
+// When the API asks to retry later, the time to wait is also returned.
+func (c *Client) fetchOnce(ctx context.Context, endpoint string) (MRReply, time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+endpoint, nil)
+	if err != nil {
+		return MRReply{}, 0, fmt.Errorf("creating request: %v", err)
+	}
+	request.Header.Set("User-Agent", c.UserAgent)
+
+	if c.RateLimiter != nil {
+		err = c.RateLimiter.Wait(ctx)
+		if errors.Is(err, ErrRateLimited) {
+			return MRReply{}, 0, &APIError{Endpoint: endpoint, Kind: ErrRateLimited, Err: fmt.Errorf("client side rate limit reached")}
+		}
+		if err != nil {
+			return MRReply{}, 0, fmt.Errorf("GET %s: %w", endpoint, err)
+		}
+	}
+
 	// Make the request
-	reply, err := Client.Get(BaseURL + endpoint)
+	atomic.AddUint64(&c.fetches, 1)
+	reply, err := c.HTTPClient.Do(request)
+	if err == nil {
+		// Closed even when the context ends right after the reply arrives
+		defer reply.Body.Close()
+	}
+	if ctx.Err() != nil {
+		// The request was canceled or took too long, which doesn't mean the API is down
+		return MRReply{}, 0, fmt.Errorf("GET %s: %w", endpoint, ctx.Err())
+	}
 	if err != nil {
-		return MRReply{}, fmt.Errorf("GET Request: %v", err)
+		return MRReply{}, 0, &APIError{Endpoint: endpoint, Kind: ErrUpstreamDown, Err: err}
+	}
+
+	switch {
+	case reply.StatusCode == http.StatusNotFound:
+		return MRReply{}, 0, &APIError{Endpoint: endpoint, StatusCode: reply.StatusCode, Kind: ErrNotFound}
+	case reply.StatusCode == http.StatusTooManyRequests:
+		return MRReply{}, retryAfter(reply), &APIError{Endpoint: endpoint, StatusCode: reply.StatusCode, Kind: ErrRateLimited}
+	case reply.StatusCode >= 500:
+		return MRReply{}, retryAfter(reply), &APIError{Endpoint: endpoint, StatusCode: reply.StatusCode, Kind: ErrUpstreamDown}
+	case reply.StatusCode != http.StatusOK:
+		return MRReply{}, 0, fmt.Errorf("GET %s: unexpected status %d", endpoint, reply.StatusCode)
 	}
 
 	// Read the reply bytes
 	replyBytes, err := ioutil.ReadAll(reply.Body)
 	if err != nil {
-		return MRReply{}, fmt.Errorf("reading reply bytes: %v", err)
+		return MRReply{}, 0, &APIError{Endpoint: endpoint, Kind: ErrUpstreamDown, Err: fmt.Errorf("reading reply bytes: %v", err)}
 	}
 
 	// Unmarshal the bytes into a MRReply
 	var res MRReply
 	err = json.Unmarshal(replyBytes, &res)
 	if err != nil {
-		return MRReply{}, fmt.Errorf("unmarshaling reply bytes into json: %v", err)
+		return MRReply{}, 0, fmt.Errorf("unmarshaling reply bytes into json: %v", err)
 	}
 
-	return res, nil
+	return res, 0, nil
+}
+
+// retryAfter returns the time to wait before retrying a request, as asked by the API
+// in the Retry-After header. Returns 0 if the API didn't ask for any time.
+func retryAfter(reply *http.Response) time.Duration {
+	value := reply.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
 }
@@ -0,0 +1,98 @@
+package ergast
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer replies to the first requests with the given statuses, and then with an empty reply
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{"MRData": {"limit": "30", "offset": "0", "total": "0"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func retryClient(url string) *Client {
+	client := NewClient(url)
+	client.Cache = nil
+	client.RateLimiter = nil
+	client.RetryBaseDelay = time.Millisecond
+	return client
+}
+
+func TestAPIGetRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		statuses []int
+		wantErr  error
+		wantReqs int32
+	}{
+		{"recovers from failures", nil, []int{503, 502}, nil, 3},
+		{"recovers from rate limiting", http.Header{"Retry-After": {"0"}}, []int{429}, nil, 2},
+		{"gives up after the retries", nil, []int{500, 500, 500, 500, 500}, ErrUpstreamDown, 1 + DefaultMaxRetries},
+		{"doesn't retry missing endpoints", nil, []int{404}, ErrNotFound, 1},
+		{"doesn't wait longer than the maximum delay", http.Header{"Retry-After": {"3600"}}, []int{429}, ErrRateLimited, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := statusServer(t, tt.header, tt.statuses...)
+
+			_, err := retryClient(server.URL).APIGet(context.Background(), "/current.json")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("APIGet returned %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(requests); got != tt.wantReqs {
+				t.Errorf("made %d requests, want %d", got, tt.wantReqs)
+			}
+		})
+	}
+}
+
+func TestAPIGetErrorDetails(t *testing.T) {
+	server, _ := statusServer(t, nil, 503, 503, 503, 503)
+
+	_, err := retryClient(server.URL).APIGet(context.Background(), "/current.json")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("APIGet returned %v, want an APIError", err)
+	}
+	if apiErr.Endpoint != "/current.json" || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("error has endpoint %q and status %d, want /current.json and 503", apiErr.Endpoint, apiErr.StatusCode)
+	}
+}
+
+func TestRequestsEscapeArguments(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte(`{"MRData": {"limit": "1000", "offset": "0", "total": "0"}}`))
+	}))
+	defer server.Close()
+
+	_, err := retryClient(server.URL).RequestRaceResults(context.Background(), "2021", "1/../../drivers")
+	if !errors.Is(err, ErrNoRaces) {
+		t.Errorf("RequestRaceResults returned %v, want ErrNoRaces", err)
+	}
+	if want := "/2021/1%2F..%2F..%2Fdrivers/results.json"; path != want {
+		t.Errorf("requested %s, want %s", path, want)
+	}
+}
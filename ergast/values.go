@@ -0,0 +1,224 @@
+package ergast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The API returns all values as strings. The accessors in this file parse them into typed values.
+// Values can be missing, like the time of a driver who didn't finish a race or the Q3 time of a driver
+// knocked out in Q1. In those cases the accessors return an error wrapping ErrMissingValue.
+
+// ErrMissingValue is returned when parsing a value that is not present in the reply
+var ErrMissingValue = errors.New("missing value")
+
+// parseInt parses the value of an integer field
+func parseInt(field, value string) (int, error) {
+	if value == "" {
+		return 0, fmt.Errorf("parsing %s: %w", field, ErrMissingValue)
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s '%s': %v", field, value, err)
+	}
+	return v, nil
+}
+
+// parseFloat parses the value of a decimal field
+func parseFloat(field, value string) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("parsing %s: %w", field, ErrMissingValue)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s '%s': %v", field, value, err)
+	}
+	return v, nil
+}
+
+// ParseLapTime parses a lap time in the format used by ergast ("1:23.456" or "59.123")
+// into a duration.
+func ParseLapTime(lapTime string) (time.Duration, error) {
+	if lapTime == "" {
+		return 0, fmt.Errorf("parsing lap time: %w", ErrMissingValue)
+	}
+
+	var minutes int
+	seconds := lapTime
+
+	if i := strings.Index(lapTime, ":"); i != -1 {
+		var err error
+		minutes, err = strconv.Atoi(lapTime[:i])
+		if err != nil {
+			return 0, fmt.Errorf("parsing minutes of lap time '%s': %v", lapTime, err)
+		}
+		seconds = lapTime[i+1:]
+	}
+
+	secs, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing seconds of lap time '%s': %v", lapTime, err)
+	}
+
+	return time.Duration(minutes)*time.Minute + time.Duration(math.Round(secs*1000))*time.Millisecond, nil
+}
+
+// SeasonYear returns the season of the race as a year
+func (r *Race) SeasonYear() (int, error) {
+	return parseInt("season", r.Season)
+}
+
+// RoundNumber returns the round of the race within its season
+func (r *Race) RoundNumber() (int, error) {
+	return parseInt("round", r.Round)
+}
+
+// PositionNumber returns the position of the result. Unclassified drivers also have a position,
+// given by the order in which they retired.
+func (rr *RaceResult) PositionNumber() (int, error) {
+	return parseInt("position", rr.Position)
+}
+
+// Classified tells if the driver was classified in the race. Drivers who retired, were
+// disqualified or didn't start have a position text like "R", "D" or "W" instead of a number.
+func (rr *RaceResult) Classified() bool {
+	_, err := strconv.Atoi(rr.PositionText)
+	return err == nil
+}
+
+// PointsValue returns the points scored in the race
+func (rr *RaceResult) PointsValue() (float64, error) {
+	return parseFloat("points", rr.Points)
+}
+
+// GridPosition returns the starting position of the driver. A grid position of 0 means
+// the driver started from the pit lane.
+func (rr *RaceResult) GridPosition() (int, error) {
+	return parseInt("grid", rr.Grid)
+}
+
+// LapsCompleted returns the number of laps completed by the driver
+func (rr *RaceResult) LapsCompleted() (int, error) {
+	return parseInt("laps", rr.Laps)
+}
+
+// Duration returns the time in milliseconds as a duration. The time is missing for drivers
+// who didn't finish on the lead lap.
+func (mt *MillisTime) Duration() (time.Duration, error) {
+	millis, err := parseInt("time in milliseconds", mt.Millis)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(millis) * time.Millisecond, nil
+}
+
+// Duration returns the lap time as a duration
+func (t *Time) Duration() (time.Duration, error) {
+	return ParseLapTime(t.Time)
+}
+
+// LapNumber returns the lap in which the fastest lap was set
+func (fl *FastestLap) LapNumber() (int, error) {
+	return parseInt("fastest lap", fl.Lap)
+}
+
+// RankNumber returns the rank of the fastest lap among the fastest laps of all drivers
+func (fl *FastestLap) RankNumber() (int, error) {
+	return parseInt("fastest lap rank", fl.Rank)
+}
+
+// SpeedValue returns the average speed in the units of the average speed
+func (as *AverageSpeed) SpeedValue() (float64, error) {
+	return parseFloat("average speed", as.Speed)
+}
+
+// Coordinates returns the latitude and longitude of the location
+func (l *Location) Coordinates() (float64, float64, error) {
+	lat, err := parseFloat("latitude", l.Lat)
+	if err != nil {
+		return 0, 0, err
+	}
+	long, err := parseFloat("longitude", l.Long)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, long, nil
+}
+
+// PositionNumber returns the qualifying position of the driver
+func (qr *QualifyingResult) PositionNumber() (int, error) {
+	return parseInt("position", qr.Position)
+}
+
+// BestTime returns the fastest lap time set by the driver across all qualifying sessions
+func (qr *QualifyingResult) BestTime() (time.Duration, error) {
+	var best time.Duration
+	for _, lapTime := range []string{qr.Q1, qr.Q2, qr.Q3} {
+		d, err := ParseLapTime(lapTime)
+		if err != nil {
+			continue
+		}
+		if best == 0 || d < best {
+			best = d
+		}
+	}
+
+	if best == 0 {
+		return 0, fmt.Errorf("parsing qualifying times: %w", ErrMissingValue)
+	}
+	return best, nil
+}
+
+// LapNumber returns the number of the lap
+func (l *Lap) LapNumber() (int, error) {
+	return parseInt("lap", l.Number)
+}
+
+// LapTime returns the time of the lap
+func (t *Timing) LapTime() (time.Duration, error) {
+	return ParseLapTime(t.Time)
+}
+
+// LapNumber returns the lap in which the pit stop was made
+func (ps *PitStop) LapNumber() (int, error) {
+	return parseInt("lap", ps.Lap)
+}
+
+// StopDuration returns the time spent in the pit lane
+func (ps *PitStop) StopDuration() (time.Duration, error) {
+	return ParseLapTime(ps.Duration)
+}
+
+// PositionNumber returns the position in the drivers championship
+func (ds *DriverStanding) PositionNumber() (int, error) {
+	return parseInt("position", ds.Position)
+}
+
+// PointsValue returns the points of the driver in the championship
+func (ds *DriverStanding) PointsValue() (float64, error) {
+	return parseFloat("points", ds.Points)
+}
+
+// WinsCount returns the number of races won by the driver in the season
+func (ds *DriverStanding) WinsCount() (int, error) {
+	return parseInt("wins", ds.Wins)
+}
+
+// PositionNumber returns the position in the constructors championship
+func (cs *ConstructorStanding) PositionNumber() (int, error) {
+	return parseInt("position", cs.Position)
+}
+
+// PointsValue returns the points of the constructor in the championship
+func (cs *ConstructorStanding) PointsValue() (float64, error) {
+	return parseFloat("points", cs.Points)
+}
+
+// WinsCount returns the number of races won by the constructor in the season
+func (cs *ConstructorStanding) WinsCount() (int, error) {
+	return parseInt("wins", cs.Wins)
+}
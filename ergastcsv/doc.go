@@ -0,0 +1,3 @@
+// Package ergastcsv answers the same queries as the ergast package, using the CSV dump
+// of the ergast database loaded in memory instead of the API.
+package ergastcsv
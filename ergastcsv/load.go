@@ -0,0 +1,77 @@
+package ergastcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// nullValue is the value used in the dump for missing fields
+const nullValue = `\N`
+
+// csvRow is a record of a csv file of the dump, with its fields accessible by column name
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+// get returns the value of a column of the row. Missing columns and null values are returned as an empty string.
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) || r.record[i] == nullValue {
+		return ""
+	}
+	return r.record[i]
+}
+
+// int returns the value of a column of the row as an integer, or 0 if the value is missing or invalid
+func (r csvRow) int(column string) int {
+	v, _ := strconv.Atoi(r.get(column))
+	return v
+}
+
+// readCSV reads a csv file of the dump in the given directory, calling fn for each record.
+// The first line of the file must be the header with the names of the columns.
+// If the file doesn't exist and is optional, no error is returned.
+func readCSV(dir, name string, optional bool, fn func(row csvRow) error) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("opening %s: %v", name, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading header of %s: %v", name, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[column] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", name, err)
+		}
+
+		err = fn(csvRow{columns: columns, record: record})
+		if err != nil {
+			return fmt.Errorf("%s, line %d: %v", name, line, err)
+		}
+	}
+}
@@ -0,0 +1,498 @@
+package ergastcsv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"f1-discord-bot/ergast"
+)
+
+// The query methods mirror the request methods of ergast.Client, answering them from the data in memory.
+// The returned values share their slices with the store, so they must not be modified.
+
+// RequestNextRace returns the first race of the current season without results
+func (s *Store) RequestNextRace(context.Context) (ergast.Race, error) {
+	current := s.currentYear()
+
+	var next *raceData
+	for i := len(s.races) - 1; i >= 0 && s.races[i].year == current; i-- {
+		if len(s.races[i].results) > 0 {
+			break
+		}
+		next = s.races[i]
+	}
+
+	if next == nil {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+	return next.race, nil
+}
+
+// RequestLastRace returns the last race of the current season with results
+func (s *Store) RequestLastRace(ctx context.Context) (ergast.Race, error) {
+	return s.RequestRaceResults(ctx, "current", "last")
+}
+
+// RequestRaceResults returns the results of a given round of a season
+func (s *Store) RequestRaceResults(_ context.Context, season, round string) (ergast.Race, error) {
+	rd := s.findRace(season, round)
+	if rd == nil || len(rd.results) == 0 {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+	return rd.withResults(rd.results), nil
+}
+
+// RequestLastQualifying returns the qualifying results of the last race
+func (s *Store) RequestLastQualifying(ctx context.Context) (ergast.Race, error) {
+	return s.RequestQualifying(ctx, "current", "last")
+}
+
+// RequestQualifying returns the qualifying results of a given round of a season
+func (s *Store) RequestQualifying(_ context.Context, season, round string) (ergast.Race, error) {
+	rd := s.findRace(season, round)
+	if rd == nil || len(rd.qualifyingResults) == 0 {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+
+	race := rd.race
+	race.QualifyingResults = rd.qualifyingResults
+	return race, nil
+}
+
+// RequestLastSprint returns the sprint results of the last race weekend
+func (s *Store) RequestLastSprint(ctx context.Context) (ergast.Race, error) {
+	return s.RequestSprint(ctx, "current", "last")
+}
+
+// RequestSprint returns the sprint results of a given round of a season
+func (s *Store) RequestSprint(_ context.Context, season, round string) (ergast.Race, error) {
+	rd := s.findRace(season, round)
+	if rd == nil || len(rd.sprintResults) == 0 {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+
+	race := rd.race
+	race.SprintResults = rd.sprintResults
+	return race, nil
+}
+
+// RequestCircuitResults returns the races held at a given circuit, each with only the result of the winner
+func (s *Store) RequestCircuitResults(_ context.Context, circuitID string) (ergast.RaceTable, error) {
+	circuitID = strings.ToLower(circuitID)
+
+	return s.raceTable(func(rd *raceData) []ergast.RaceResult {
+		if rd.race.Circuit.CircuitID != circuitID {
+			return nil
+		}
+		return filterResults(rd.results, func(result ergast.RaceResult) bool {
+			return result.Position == "1"
+		})
+	})
+}
+
+// RequestDriverResults returns the races of a given driver, each with only the result of the driver
+func (s *Store) RequestDriverResults(_ context.Context, driverID string) (ergast.RaceTable, error) {
+	driverID = strings.ToLower(driverID)
+
+	return s.raceTable(func(rd *raceData) []ergast.RaceResult {
+		return filterResults(rd.results, func(result ergast.RaceResult) bool {
+			return result.Driver.DriverID == driverID
+		})
+	})
+}
+
+// RequestDriverLastResults returns the last n races of a given driver, each with only the result of the driver
+func (s *Store) RequestDriverLastResults(ctx context.Context, driverID string, n int) (ergast.RaceTable, error) {
+	table, err := s.RequestDriverResults(ctx, driverID)
+	return lastRaces(table, n), err
+}
+
+// RequestDriverPoleCount returns the number of times a given driver qualified in first place
+func (s *Store) RequestDriverPoleCount(_ context.Context, driverID string) (int, error) {
+	driverID = strings.ToLower(driverID)
+
+	var count int
+	for _, rd := range s.races {
+		for _, result := range rd.qualifyingResults {
+			if result.Driver.DriverID == driverID && result.Position == "1" {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// RequestLaps returns the lap times of a driver in a given round of a season
+func (s *Store) RequestLaps(_ context.Context, season, round, driverID string) (ergast.Race, error) {
+	rd := s.findRace(season, round)
+	if rd == nil {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+	driverID = strings.ToLower(driverID)
+
+	var laps []ergast.Lap
+	for _, lap := range rd.laps {
+		for _, timing := range lap.Timings {
+			if timing.DriverID == driverID {
+				laps = append(laps, ergast.Lap{Number: lap.Number, Timings: []ergast.Timing{timing}})
+				break
+			}
+		}
+	}
+
+	if len(laps) == 0 {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+
+	race := rd.race
+	race.Laps = laps
+	return race, nil
+}
+
+// RequestPitStops returns the pit stops made in a given round of a season
+func (s *Store) RequestPitStops(_ context.Context, season, round string) (ergast.Race, error) {
+	rd := s.findRace(season, round)
+	if rd == nil || len(rd.pitStops) == 0 {
+		return ergast.Race{}, ergast.ErrNoRaces
+	}
+
+	race := rd.race
+	race.PitStops = rd.pitStops
+	return race, nil
+}
+
+// RequestConstructorLastResults returns the last n races of a given constructor, each with only the results of its cars
+func (s *Store) RequestConstructorLastResults(_ context.Context, constructorID string, n int) (ergast.RaceTable, error) {
+	constructorID = strings.ToLower(constructorID)
+
+	table, err := s.raceTable(func(rd *raceData) []ergast.RaceResult {
+		return filterResults(rd.results, func(result ergast.RaceResult) bool {
+			return result.Constructor.ConstructorID == constructorID
+		})
+	})
+	return lastRaces(table, n), err
+}
+
+// RequestConstructorSeasons returns the seasons in which a given constructor took part
+func (s *Store) RequestConstructorSeasons(_ context.Context, constructorID string) (ergast.SeasonTable, error) {
+	constructorID = strings.ToLower(constructorID)
+
+	years := make(map[string]bool)
+	for _, rd := range s.races {
+		for _, result := range rd.results {
+			if result.Constructor.ConstructorID == constructorID {
+				years[rd.race.Season] = true
+				break
+			}
+		}
+	}
+
+	var table ergast.SeasonTable
+	for _, season := range s.seasons {
+		if years[season.Year] {
+			table.Seasons = append(table.Seasons, season)
+		}
+	}
+
+	if len(table.Seasons) == 0 {
+		return ergast.SeasonTable{}, fmt.Errorf("empty list of seasons from csv dump")
+	}
+	return table, nil
+}
+
+// RequestConstructorDrivers returns the drivers that raced for a given constructor
+func (s *Store) RequestConstructorDrivers(_ context.Context, constructorID string) (ergast.DriverTable, error) {
+	constructorID = strings.ToLower(constructorID)
+
+	seen := make(map[string]bool)
+	var table ergast.DriverTable
+	for _, rd := range s.races {
+		for _, result := range rd.results {
+			if result.Constructor.ConstructorID == constructorID && !seen[result.Driver.DriverID] {
+				seen[result.Driver.DriverID] = true
+				table.Drivers = append(table.Drivers, result.Driver)
+			}
+		}
+	}
+
+	if len(table.Drivers) == 0 {
+		return ergast.DriverTable{}, fmt.Errorf("empty list of drivers from csv dump")
+	}
+
+	sort.Slice(table.Drivers, func(i, j int) bool {
+		return table.Drivers[i].DriverID < table.Drivers[j].DriverID
+	})
+	return table, nil
+}
+
+// RequestConstructorChampionships returns the final standings of the seasons in which a given
+// constructor won the constructors championship, with only the standing of the constructor.
+// The table is empty if the constructor never won the championship.
+func (s *Store) RequestConstructorChampionships(_ context.Context, constructorID string) (ergast.StandingsTable, error) {
+	constructorID = strings.ToLower(constructorID)
+
+	var table ergast.StandingsTable
+	for _, rd := range s.finalStandings(func(rd *raceData) bool { return len(rd.constructorStandings) > 0 }) {
+		for _, standing := range rd.constructorStandings {
+			if standing.Position == "1" && standing.Constructor.ConstructorID == constructorID {
+				table.StandingsLists = append(table.StandingsLists, ergast.StandingsList{
+					Season:               rd.race.Season,
+					Round:                rd.race.Round,
+					ConstructorStandings: []ergast.ConstructorStanding{standing},
+				})
+			}
+		}
+	}
+	return table, nil
+}
+
+// RequestConstructorPositionCount returns the number of times the cars of a given
+// constructor finished a race in a given position
+func (s *Store) RequestConstructorPositionCount(_ context.Context, constructorID string, position string) (int, error) {
+	constructorID = strings.ToLower(constructorID)
+
+	var count int
+	for _, rd := range s.races {
+		for _, result := range rd.results {
+			if result.Constructor.ConstructorID == constructorID && result.Position == position {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// CurrentSeason returns the races of the current season
+func (s *Store) CurrentSeason(ctx context.Context) (ergast.RaceTable, error) {
+	return s.RequestSeason(ctx, "current")
+}
+
+// RequestDriverStandings returns the drivers championship standings for a given season,
+// after the last race with standings. The season can be a year or "current".
+func (s *Store) RequestDriverStandings(_ context.Context, season string) (ergast.StandingsList, error) {
+	rd := s.lastRaceWith(s.year(season), func(rd *raceData) bool { return len(rd.driverStandings) > 0 })
+	if rd == nil {
+		return ergast.StandingsList{}, ergast.ErrNoStandings
+	}
+
+	return ergast.StandingsList{
+		Season:          rd.race.Season,
+		Round:           rd.race.Round,
+		DriverStandings: rd.driverStandings,
+	}, nil
+}
+
+// RequestConstructorStandings returns the constructors championship standings for a given season,
+// after the last race with standings. The season can be a year or "current".
+func (s *Store) RequestConstructorStandings(_ context.Context, season string) (ergast.StandingsList, error) {
+	rd := s.lastRaceWith(s.year(season), func(rd *raceData) bool { return len(rd.constructorStandings) > 0 })
+	if rd == nil {
+		return ergast.StandingsList{}, ergast.ErrNoStandings
+	}
+
+	return ergast.StandingsList{
+		Season:               rd.race.Season,
+		Round:                rd.race.Round,
+		ConstructorStandings: rd.constructorStandings,
+	}, nil
+}
+
+// RequestSeason returns the races of a given season, without results.
+// The season can be a year or "current".
+func (s *Store) RequestSeason(_ context.Context, season string) (ergast.RaceTable, error) {
+	year := s.year(season)
+
+	table := ergast.RaceTable{Season: strconv.Itoa(year)}
+	for _, rd := range s.races {
+		if rd.year == year {
+			table.Races = append(table.Races, rd.race)
+		}
+	}
+
+	if len(table.Races) == 0 {
+		return ergast.RaceTable{}, ergast.ErrNoRaces
+	}
+	return table, nil
+}
+
+// RequestSeasonWinners returns the winners of all the races already held in a given season.
+// Each race of the table only has the result of the winner.
+func (s *Store) RequestSeasonWinners(_ context.Context, season string) (ergast.RaceTable, error) {
+	year := s.year(season)
+
+	table, err := s.raceTable(func(rd *raceData) []ergast.RaceResult {
+		if rd.year != year {
+			return nil
+		}
+		return filterResults(rd.results, func(result ergast.RaceResult) bool {
+			return result.Position == "1"
+		})
+	})
+	if err != nil {
+		return ergast.RaceTable{}, err
+	}
+
+	table.Season = strconv.Itoa(year)
+	return table, nil
+}
+
+// Circuits returns the list of circuits, sorted by id
+func (s *Store) Circuits(context.Context) (ergast.CircuitTable, error) {
+	var table ergast.CircuitTable
+	for _, circuit := range s.circuits {
+		table.Circuits = append(table.Circuits, circuit)
+	}
+
+	if len(table.Circuits) == 0 {
+		return ergast.CircuitTable{}, fmt.Errorf("empty list of circuits from csv dump")
+	}
+
+	sort.Slice(table.Circuits, func(i, j int) bool {
+		return table.Circuits[i].CircuitID < table.Circuits[j].CircuitID
+	})
+	return table, nil
+}
+
+// Drivers returns the list of drivers, sorted by id
+func (s *Store) Drivers(context.Context) (ergast.DriverTable, error) {
+	var table ergast.DriverTable
+	for _, driver := range s.drivers {
+		table.Drivers = append(table.Drivers, driver)
+	}
+
+	if len(table.Drivers) == 0 {
+		return ergast.DriverTable{}, fmt.Errorf("empty list of drivers from csv dump")
+	}
+
+	sort.Slice(table.Drivers, func(i, j int) bool {
+		return table.Drivers[i].DriverID < table.Drivers[j].DriverID
+	})
+	return table, nil
+}
+
+// Constructors returns the list of constructors, sorted by id
+func (s *Store) Constructors(context.Context) (ergast.ConstructorTable, error) {
+	var table ergast.ConstructorTable
+	for _, constructor := range s.constructors {
+		table.Constructors = append(table.Constructors, constructor)
+	}
+
+	if len(table.Constructors) == 0 {
+		return ergast.ConstructorTable{}, fmt.Errorf("empty list of constructors from csv dump")
+	}
+
+	sort.Slice(table.Constructors, func(i, j int) bool {
+		return table.Constructors[i].ConstructorID < table.Constructors[j].ConstructorID
+	})
+	return table, nil
+}
+
+// Seasons returns the list of seasons, sorted by year
+func (s *Store) Seasons(context.Context) (ergast.SeasonTable, error) {
+	if len(s.seasons) == 0 {
+		return ergast.SeasonTable{}, fmt.Errorf("empty list of seasons from csv dump")
+	}
+	return ergast.SeasonTable{Seasons: s.seasons}, nil
+}
+
+// currentYear returns the most recent season of the dump
+func (s *Store) currentYear() int {
+	if len(s.races) == 0 {
+		return 0
+	}
+	return s.races[len(s.races)-1].year
+}
+
+// year returns the year of a season given as a year or "current", or 0 if the season is invalid
+func (s *Store) year(season string) int {
+	if strings.ToLower(season) == "current" {
+		return s.currentYear()
+	}
+	return atoi(season)
+}
+
+// findRace returns the race of a given round of a season, or nil if there is no such race.
+// The round can be a number or "last", for the last race of the season with results.
+func (s *Store) findRace(season, round string) *raceData {
+	year := s.year(season)
+
+	if strings.ToLower(round) == "last" {
+		return s.lastRaceWith(year, func(rd *raceData) bool { return len(rd.results) > 0 })
+	}
+
+	for _, rd := range s.races {
+		if rd.year == year && rd.race.Round == round {
+			return rd
+		}
+	}
+	return nil
+}
+
+// lastRaceWith returns the last race of a season for which has returns true, or nil if there is none
+func (s *Store) lastRaceWith(year int, has func(rd *raceData) bool) *raceData {
+	for i := len(s.races) - 1; i >= 0; i-- {
+		rd := s.races[i]
+		if rd.year == year && has(rd) {
+			return rd
+		}
+	}
+	return nil
+}
+
+// finalStandings returns the last race of each finished season, for the seasons in which has returns true
+// for that race. The season being held has no data for its last race yet, so it is left out.
+func (s *Store) finalStandings(has func(rd *raceData) bool) []*raceData {
+	var res []*raceData
+	for i, rd := range s.races {
+		lastOfSeason := i == len(s.races)-1 || s.races[i+1].year != rd.year
+		if lastOfSeason && has(rd) {
+			res = append(res, rd)
+		}
+	}
+	return res
+}
+
+// raceTable returns a table with the races for which results returns some results,
+// each race having only those results
+func (s *Store) raceTable(results func(rd *raceData) []ergast.RaceResult) (ergast.RaceTable, error) {
+	var table ergast.RaceTable
+	for _, rd := range s.races {
+		if raceResults := results(rd); len(raceResults) > 0 {
+			table.Races = append(table.Races, rd.withResults(raceResults))
+		}
+	}
+
+	if len(table.Races) == 0 {
+		return ergast.RaceTable{}, ergast.ErrNoRaces
+	}
+	return table, nil
+}
+
+// withResults returns the race with the given results
+func (rd *raceData) withResults(results []ergast.RaceResult) ergast.Race {
+	race := rd.race
+	race.Results = results
+	return race
+}
+
+// lastRaces trims a table to its last n races
+func lastRaces(table ergast.RaceTable, n int) ergast.RaceTable {
+	if len(table.Races) > n {
+		table.Races = table.Races[len(table.Races)-n:]
+	}
+	return table
+}
+
+// filterResults returns the results for which keep returns true
+func filterResults(results []ergast.RaceResult, keep func(result ergast.RaceResult) bool) []ergast.RaceResult {
+	var res []ergast.RaceResult
+	for _, result := range results {
+		if keep(result) {
+			res = append(res, result)
+		}
+	}
+	return res
+}
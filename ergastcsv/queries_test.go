@@ -0,0 +1,261 @@
+package ergastcsv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"f1-discord-bot/ergast"
+)
+
+func TestFindRace(t *testing.T) {
+	s := loadFixture(t)
+
+	tests := []struct {
+		season, round string
+		want          string // season/round of the race, or empty if there is none
+	}{
+		{"current", "last", "2023/2"},
+		{"Current", "LAST", "2023/2"},
+		{"current", "3", "2023/3"},
+		{"2019", "last", "2019/13"},
+		{"2019", "12", "2019/12"},
+		{"2019", "1", ""},
+		{"2020", "last", ""},
+		{"season", "1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.season+"/"+tt.round, func(t *testing.T) {
+			var got string
+			if rd := s.findRace(tt.season, tt.round); rd != nil {
+				got = rd.race.Season + "/" + rd.race.Round
+			}
+			if got != tt.want {
+				t.Errorf("findRace(%q, %q) = %q, want %q", tt.season, tt.round, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestRaceResults(t *testing.T) {
+	s := loadFixture(t)
+
+	race, err := s.RequestRaceResults(context.Background(), "current", "last")
+	if err != nil {
+		t.Fatalf("RequestRaceResults: %v", err)
+	}
+
+	var wantRace ergast.Race
+	decode(t, `{
+		"season": "2023", "round": "2", "url": "https://en.wikipedia.org/wiki/2023_Saudi_Arabian_Grand_Prix",
+		"raceName": "Saudi Arabian Grand Prix",
+		"Circuit": {
+			"circuitId": "jeddah", "url": "http://en.wikipedia.org/wiki/Jeddah_Street_Circuit", "circuitName": "Jeddah Corniche Circuit",
+			"Location": {"lat": "21.6319", "long": "39.1044", "locality": "Jeddah", "country": "Saudi Arabia"}
+		},
+		"date": "2023-03-19", "time": "17:00:00Z",
+		"FirstPractice": {"date": "2023-03-17", "time": "13:30:00Z"},
+		"SecondPractice": {"date": "2023-03-17", "time": "17:00:00Z"},
+		"ThirdPractice": {"date": "2023-03-18", "time": "13:30:00Z"},
+		"Qualifying": {"date": "2023-03-18", "time": "17:00:00Z"}
+	}`, &wantRace)
+
+	results := race.Results
+	race.Results = nil
+	if !reflect.DeepEqual(race, wantRace) {
+		t.Errorf("got race\n%+v\nwant\n%+v", race, wantRace)
+	}
+
+	var wantWinner ergast.RaceResult
+	decode(t, `{
+		"number": "11", "position": "1", "positionText": "1", "points": "25",
+		"Driver": {
+			"driverId": "perez", "permanentNumber": "11", "code": "PER", "url": "http://en.wikipedia.org/wiki/Sergio_P%C3%A9rez",
+			"givenName": "Sergio", "familyName": "Pérez", "dateOfBirth": "1990-01-26", "nationality": "Mexican"
+		},
+		"Constructor": {"constructorId": "red_bull", "url": "http://en.wikipedia.org/wiki/Red_Bull_Racing", "name": "Red Bull", "nationality": "Austrian"},
+		"grid": "1", "laps": "50", "status": "Finished",
+		"Time": {"millis": "4874894", "time": "1:21:14.894"},
+		"FastestLap": {"rank": "2", "lap": "47", "Time": {"time": "1:31.906"}, "AverageSpeed": {"units": "kph", "speed": "241.855"}}
+	}`, &wantWinner)
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	if !reflect.DeepEqual(results[0], wantWinner) {
+		t.Errorf("got winner\n%+v\nwant\n%+v", results[0], wantWinner)
+	}
+
+	// Retired drivers are classified last, with no position, like in the API
+	if last := results[3]; last.Driver.DriverID != "albon" || last.PositionText != "R" || last.Status != "Brakes" {
+		t.Errorf("got last result %s %s %s, want albon R Brakes", last.Driver.DriverID, last.PositionText, last.Status)
+	}
+}
+
+func TestRequestNextRace(t *testing.T) {
+	tests := []struct {
+		name      string
+		races     func(races []*raceData) []*raceData
+		wantRound string
+		wantErr   error
+	}{
+		{"race without results", nil, "3", nil},
+		{"season over", func(races []*raceData) []*raceData { return races[:4] }, "", ergast.ErrNoRaces},
+		{"no races", func(races []*raceData) []*raceData { return nil }, "", ergast.ErrNoRaces},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := loadFixture(t)
+			if tt.races != nil {
+				s.races = tt.races(s.races)
+			}
+
+			race, err := s.RequestNextRace(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestNextRace returned %v, want %v", err, tt.wantErr)
+			}
+			if race.Round != tt.wantRound {
+				t.Errorf("got round %q, want %q", race.Round, tt.wantRound)
+			}
+		})
+	}
+}
+
+func TestRequestDriverStandings(t *testing.T) {
+	s := loadFixture(t)
+
+	tests := []struct {
+		season    string
+		wantRound string
+		// wantConstructors are the constructors of each driver, in the order the driver raced for them
+		wantConstructors map[string][]string
+	}{
+		{"current", "2", map[string][]string{
+			"max_verstappen": {"red_bull"},
+			"perez":          {"red_bull"},
+			"hamilton":       {"mercedes"},
+			"albon":          {"williams"},
+		}},
+		// Gasly and Albon swapped seats after the Hungarian Grand Prix
+		{"2019", "13", map[string][]string{
+			"hamilton":       {"mercedes"},
+			"max_verstappen": {"red_bull"},
+			"gasly":          {"red_bull", "toro_rosso"},
+			"albon":          {"toro_rosso", "red_bull"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.season, func(t *testing.T) {
+			standings, err := s.RequestDriverStandings(context.Background(), tt.season)
+			if err != nil {
+				t.Fatalf("RequestDriverStandings: %v", err)
+			}
+			if standings.Round != tt.wantRound {
+				t.Errorf("got standings after round %s, want %s", standings.Round, tt.wantRound)
+			}
+
+			constructors := make(map[string][]string)
+			for _, standing := range standings.DriverStandings {
+				var ids []string
+				for _, constructor := range standing.Constructors {
+					ids = append(ids, constructor.ConstructorID)
+				}
+				constructors[standing.Driver.DriverID] = ids
+			}
+			if !reflect.DeepEqual(constructors, tt.wantConstructors) {
+				t.Errorf("got constructors %v, want %v", constructors, tt.wantConstructors)
+			}
+		})
+	}
+
+	// The whole standing has the same shape as the one of the API
+	standings, _ := s.RequestDriverStandings(context.Background(), "current")
+
+	var want ergast.StandingsList
+	decode(t, `{
+		"season": "2023", "round": "2",
+		"DriverStandings": [{
+			"position": "1", "positionText": "1", "points": "44", "wins": "1",
+			"Driver": {
+				"driverId": "max_verstappen", "permanentNumber": "33", "code": "VER", "url": "http://en.wikipedia.org/wiki/Max_Verstappen",
+				"givenName": "Max", "familyName": "Verstappen", "dateOfBirth": "1997-09-30", "nationality": "Dutch"
+			},
+			"Constructors": [{"constructorId": "red_bull", "url": "http://en.wikipedia.org/wiki/Red_Bull_Racing", "name": "Red Bull", "nationality": "Austrian"}]
+		}]
+	}`, &want)
+
+	standings.DriverStandings = standings.DriverStandings[:1]
+	if !reflect.DeepEqual(standings, want) {
+		t.Errorf("got standings\n%+v\nwant\n%+v", standings, want)
+	}
+}
+
+func TestRequestConstructorStandings(t *testing.T) {
+	s := loadFixture(t)
+
+	tests := []struct {
+		season    string
+		wantRound string
+		wantOrder []string
+		wantErr   error
+	}{
+		{"current", "2", []string{"red_bull", "mercedes", "williams"}, nil},
+		{"2019", "13", []string{"mercedes", "red_bull", "toro_rosso"}, nil},
+		{"2020", "", nil, ergast.ErrNoStandings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.season, func(t *testing.T) {
+			standings, err := s.RequestConstructorStandings(context.Background(), tt.season)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestConstructorStandings returned %v, want %v", err, tt.wantErr)
+			}
+			if standings.Round != tt.wantRound {
+				t.Errorf("got standings after round %q, want %q", standings.Round, tt.wantRound)
+			}
+
+			var order []string
+			for _, standing := range standings.ConstructorStandings {
+				order = append(order, standing.Constructor.ConstructorID)
+			}
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("got constructors %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestRequestConstructorChampionships(t *testing.T) {
+	s := loadFixture(t)
+
+	tests := []struct {
+		constructorID string
+		wantSeasons   []string
+	}{
+		{"mercedes", []string{"2019"}},
+		// Red Bull leads 2023, but the season isn't over
+		{"red_bull", nil},
+		{"williams", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constructorID, func(t *testing.T) {
+			table, err := s.RequestConstructorChampionships(context.Background(), tt.constructorID)
+			if err != nil {
+				t.Fatalf("RequestConstructorChampionships: %v", err)
+			}
+
+			var seasons []string
+			for _, list := range table.StandingsLists {
+				seasons = append(seasons, list.Season)
+			}
+			if !reflect.DeepEqual(seasons, tt.wantSeasons) {
+				t.Errorf("got championships in %v, want %v", seasons, tt.wantSeasons)
+			}
+		})
+	}
+}
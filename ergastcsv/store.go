@@ -0,0 +1,442 @@
+package ergastcsv
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"f1-discord-bot/ergast"
+)
+
+// Store holds the ergast database loaded from its CSV dump
+type Store struct {
+	circuits     map[string]ergast.Circuit
+	drivers      map[string]ergast.Driver
+	constructors map[string]ergast.Constructor
+	seasons      []ergast.Season
+
+	// races sorted by season and round
+	races []*raceData
+}
+
+// raceData contains all the data of a race weekend
+type raceData struct {
+	// race has the schedule and circuit of the race, with no results
+	race ergast.Race
+	year int
+
+	results              []ergast.RaceResult
+	sprintResults        []ergast.RaceResult
+	qualifyingResults    []ergast.QualifyingResult
+	laps                 []ergast.Lap
+	pitStops             []ergast.PitStop
+	driverStandings      []ergast.DriverStanding
+	constructorStandings []ergast.ConstructorStanding
+}
+
+// Load loads the CSV files of the ergast database dump present in a directory.
+// The files with the circuits, constructors, drivers, races, results and statuses are required,
+// the others (seasons, sprint results, qualifying, standings, lap times and pit stops) are optional.
+func Load(dir string) (*Store, error) {
+	s := &Store{
+		circuits:     make(map[string]ergast.Circuit),
+		drivers:      make(map[string]ergast.Driver),
+		constructors: make(map[string]ergast.Constructor),
+	}
+
+	// The files reference each other by numeric ids, while the API uses references like "hamilton"
+	circuitRefs := make(map[string]string)
+	driverRefs := make(map[string]string)
+	constructorRefs := make(map[string]string)
+	statuses := make(map[string]string)
+	races := make(map[string]*raceData)
+
+	err := readCSV(dir, "circuits.csv", false, func(row csvRow) error {
+		circuit := ergast.Circuit{
+			CircuitID:   row.get("circuitRef"),
+			URL:         row.get("url"),
+			CircuitName: row.get("name"),
+			Location: ergast.Location{
+				Lat:      row.get("lat"),
+				Long:     row.get("lng"),
+				Locality: row.get("location"),
+				Country:  row.get("country"),
+			},
+		}
+		circuitRefs[row.get("circuitId")] = circuit.CircuitID
+		s.circuits[circuit.CircuitID] = circuit
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "drivers.csv", false, func(row csvRow) error {
+		driver := ergast.Driver{
+			DriverID:        row.get("driverRef"),
+			URL:             row.get("url"),
+			GivenName:       row.get("forename"),
+			FamilyName:      row.get("surname"),
+			DateOfBirth:     row.get("dob"),
+			Nationality:     row.get("nationality"),
+			Code:            row.get("code"),
+			PermanentNumber: row.get("number"),
+		}
+		driverRefs[row.get("driverId")] = driver.DriverID
+		s.drivers[driver.DriverID] = driver
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "constructors.csv", false, func(row csvRow) error {
+		constructor := ergast.Constructor{
+			ConstructorID: row.get("constructorRef"),
+			URL:           row.get("url"),
+			Name:          row.get("name"),
+			Nationality:   row.get("nationality"),
+		}
+		constructorRefs[row.get("constructorId")] = constructor.ConstructorID
+		s.constructors[constructor.ConstructorID] = constructor
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "status.csv", false, func(row csvRow) error {
+		statuses[row.get("statusId")] = row.get("status")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "races.csv", false, func(row csvRow) error {
+		circuit, ok := s.circuits[circuitRefs[row.get("circuitId")]]
+		if !ok {
+			return fmt.Errorf("unknown circuit %s", row.get("circuitId"))
+		}
+
+		rd := &raceData{
+			year: row.int("year"),
+			race: ergast.Race{
+				Season:   row.get("year"),
+				Round:    row.get("round"),
+				URL:      row.get("url"),
+				RaceName: row.get("name"),
+				Circuit:  circuit,
+				DateTime: ergast.DateTime{Date: row.get("date"), Time: apiTime(row.get("time"))},
+
+				FirstPractice:  session(row, "fp1"),
+				SecondPractice: session(row, "fp2"),
+				ThirdPractice:  session(row, "fp3"),
+				Qualifying:     session(row, "quali"),
+				Sprint:         session(row, "sprint"),
+			},
+		}
+		races[row.get("raceId")] = rd
+		s.races = append(s.races, rd)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(s.races, func(i, j int) bool {
+		a, b := s.races[i], s.races[j]
+		if a.year != b.year {
+			return a.year < b.year
+		}
+		return atoi(a.race.Round) < atoi(b.race.Round)
+	})
+
+	err = readCSV(dir, "seasons.csv", true, func(row csvRow) error {
+		s.seasons = append(s.seasons, ergast.Season{Year: row.get("year"), URL: row.get("url")})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(s.seasons) == 0 {
+		// Without the seasons file, the seasons are the ones with races
+		for _, rd := range s.races {
+			if len(s.seasons) == 0 || s.seasons[len(s.seasons)-1].Year != rd.race.Season {
+				s.seasons = append(s.seasons, ergast.Season{Year: rd.race.Season})
+			}
+		}
+	}
+	sort.Slice(s.seasons, func(i, j int) bool {
+		return atoi(s.seasons[i].Year) < atoi(s.seasons[j].Year)
+	})
+
+	// A result row is the same in the results and sprint results files
+	readResult := func(row csvRow) (*raceData, ergast.RaceResult, error) {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return nil, ergast.RaceResult{}, fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+
+		result := ergast.RaceResult{
+			Number:       row.get("number"),
+			Position:     row.get("positionOrder"),
+			PositionText: row.get("positionText"),
+			Points:       row.get("points"),
+			Driver:       s.drivers[driverRefs[row.get("driverId")]],
+			Constructor:  s.constructors[constructorRefs[row.get("constructorId")]],
+			Grid:         row.get("grid"),
+			Laps:         row.get("laps"),
+			Status:       statuses[row.get("statusId")],
+			Time: ergast.MillisTime{
+				Millis: row.get("milliseconds"),
+				Time:   row.get("time"),
+			},
+			FastestLap: ergast.FastestLap{
+				Rank: row.get("rank"),
+				Lap:  row.get("fastestLap"),
+				Time: ergast.Time{Time: row.get("fastestLapTime")},
+			},
+		}
+		if speed := row.get("fastestLapSpeed"); speed != "" {
+			result.FastestLap.AverageSpeed = ergast.AverageSpeed{Units: "kph", Speed: speed}
+		}
+
+		return rd, result, nil
+	}
+
+	err = readCSV(dir, "results.csv", false, func(row csvRow) error {
+		rd, result, err := readResult(row)
+		if err != nil {
+			return err
+		}
+		rd.results = append(rd.results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "sprint_results.csv", true, func(row csvRow) error {
+		rd, result, err := readResult(row)
+		if err != nil {
+			return err
+		}
+		rd.sprintResults = append(rd.sprintResults, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "qualifying.csv", true, func(row csvRow) error {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+		rd.qualifyingResults = append(rd.qualifyingResults, ergast.QualifyingResult{
+			Number:      row.get("number"),
+			Position:    row.get("position"),
+			Driver:      s.drivers[driverRefs[row.get("driverId")]],
+			Constructor: s.constructors[constructorRefs[row.get("constructorId")]],
+			Q1:          row.get("q1"),
+			Q2:          row.get("q2"),
+			Q3:          row.get("q3"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "driver_standings.csv", true, func(row csvRow) error {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+		rd.driverStandings = append(rd.driverStandings, ergast.DriverStanding{
+			Position:     row.get("position"),
+			PositionText: row.get("positionText"),
+			Points:       row.get("points"),
+			Wins:         row.get("wins"),
+			Driver:       s.drivers[driverRefs[row.get("driverId")]],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "constructor_standings.csv", true, func(row csvRow) error {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+		rd.constructorStandings = append(rd.constructorStandings, ergast.ConstructorStanding{
+			Position:     row.get("position"),
+			PositionText: row.get("positionText"),
+			Points:       row.get("points"),
+			Wins:         row.get("wins"),
+			Constructor:  s.constructors[constructorRefs[row.get("constructorId")]],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Lap times are grouped by lap while loading, then sorted
+	lapIndexes := make(map[*raceData]map[string]int)
+	err = readCSV(dir, "lap_times.csv", true, func(row csvRow) error {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+
+		indexes, ok := lapIndexes[rd]
+		if !ok {
+			indexes = make(map[string]int)
+			lapIndexes[rd] = indexes
+		}
+
+		number := row.get("lap")
+		i, ok := indexes[number]
+		if !ok {
+			i = len(rd.laps)
+			indexes[number] = i
+			rd.laps = append(rd.laps, ergast.Lap{Number: number})
+		}
+
+		rd.laps[i].Timings = append(rd.laps[i].Timings, ergast.Timing{
+			DriverID: driverRefs[row.get("driverId")],
+			Position: row.get("position"),
+			Time:     row.get("time"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "pit_stops.csv", true, func(row csvRow) error {
+		rd, ok := races[row.get("raceId")]
+		if !ok {
+			return fmt.Errorf("unknown race %s", row.get("raceId"))
+		}
+		rd.pitStops = append(rd.pitStops, ergast.PitStop{
+			DriverID: driverRefs[row.get("driverId")],
+			Lap:      row.get("lap"),
+			Stop:     row.get("stop"),
+			Time:     row.get("time"),
+			Duration: row.get("duration"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rd := range s.races {
+		rd.sortData()
+	}
+	s.fillStandingsConstructors()
+
+	return s, nil
+}
+
+// sortData sorts the data of the race in the same order the API returns it
+func (rd *raceData) sortData() {
+	byPosition := func(results []ergast.RaceResult) {
+		sort.SliceStable(results, func(i, j int) bool {
+			return atoi(results[i].Position) < atoi(results[j].Position)
+		})
+	}
+	byPosition(rd.results)
+	byPosition(rd.sprintResults)
+
+	sort.SliceStable(rd.qualifyingResults, func(i, j int) bool {
+		return atoi(rd.qualifyingResults[i].Position) < atoi(rd.qualifyingResults[j].Position)
+	})
+
+	sort.SliceStable(rd.laps, func(i, j int) bool {
+		return atoi(rd.laps[i].Number) < atoi(rd.laps[j].Number)
+	})
+	for _, lap := range rd.laps {
+		timings := lap.Timings
+		sort.SliceStable(timings, func(i, j int) bool {
+			return atoi(timings[i].Position) < atoi(timings[j].Position)
+		})
+	}
+
+	sort.SliceStable(rd.pitStops, func(i, j int) bool {
+		if rd.pitStops[i].Time != rd.pitStops[j].Time {
+			return rd.pitStops[i].Time < rd.pitStops[j].Time
+		}
+		return rd.pitStops[i].DriverID < rd.pitStops[j].DriverID
+	})
+
+	sort.SliceStable(rd.driverStandings, func(i, j int) bool {
+		return atoi(rd.driverStandings[i].Position) < atoi(rd.driverStandings[j].Position)
+	})
+	sort.SliceStable(rd.constructorStandings, func(i, j int) bool {
+		return atoi(rd.constructorStandings[i].Position) < atoi(rd.constructorStandings[j].Position)
+	})
+}
+
+// fillStandingsConstructors fills the constructors each driver drove for in the driver standings,
+// which the dump doesn't have, from the results of the season up to each race
+func (s *Store) fillStandingsConstructors() {
+	var year int
+	var constructors map[string][]ergast.Constructor
+
+	for _, rd := range s.races {
+		if rd.year != year {
+			year = rd.year
+			constructors = make(map[string][]ergast.Constructor)
+		}
+
+		for _, result := range rd.results {
+			driverConstructors := constructors[result.Driver.DriverID]
+			if !hasConstructor(driverConstructors, result.Constructor.ConstructorID) {
+				constructors[result.Driver.DriverID] = append(driverConstructors, result.Constructor)
+			}
+		}
+
+		for i := range rd.driverStandings {
+			driverID := rd.driverStandings[i].Driver.DriverID
+			rd.driverStandings[i].Constructors = append([]ergast.Constructor(nil), constructors[driverID]...)
+		}
+	}
+}
+
+func hasConstructor(constructors []ergast.Constructor, constructorID string) bool {
+	for _, constructor := range constructors {
+		if constructor.ConstructorID == constructorID {
+			return true
+		}
+	}
+	return false
+}
+
+// session returns the schedule of a session of a race, or nil if the race doesn't have it
+func session(row csvRow, prefix string) *ergast.DateTime {
+	date := row.get(prefix + "_date")
+	if date == "" {
+		return nil
+	}
+	return &ergast.DateTime{Date: date, Time: apiTime(row.get(prefix + "_time"))}
+}
+
+// apiTime converts a time of the dump to the format of the API, which is in UTC
+func apiTime(t string) string {
+	if t == "" {
+		return ""
+	}
+	return t + "Z"
+}
+
+// atoi converts a string to an integer, returning 0 if the string is not a valid integer
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
@@ -0,0 +1,136 @@
+package ergastcsv
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtureDir has a small dump with two races of 2019 and three of 2023, the last one without results yet
+const fixtureDir = "testdata"
+
+func loadFixture(t *testing.T) *Store {
+	t.Helper()
+	s, err := Load(fixtureDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return s
+}
+
+// decode decodes a value in the format of the API, to compare against the ones of the store
+func decode(t *testing.T, data string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+}
+
+// fixtureCopy copies the fixture to a temporary directory, leaving out the files in skip and
+// replacing the ones in replace
+func fixtureCopy(t *testing.T, skip []string, replace map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	entries, err := os.ReadDir(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(fixtureDir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range skip {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, data := range replace {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	circuits, err := s.Circuits(ctx)
+	if err != nil || len(circuits.Circuits) != 5 {
+		t.Errorf("Circuits returned %d circuits and error %v, want 5", len(circuits.Circuits), err)
+	}
+	drivers, err := s.Drivers(ctx)
+	if err != nil || len(drivers.Drivers) != 5 {
+		t.Errorf("Drivers returned %d drivers and error %v, want 5", len(drivers.Drivers), err)
+	}
+	constructors, err := s.Constructors(ctx)
+	if err != nil || len(constructors.Constructors) != 4 {
+		t.Errorf("Constructors returned %d constructors and error %v, want 4", len(constructors.Constructors), err)
+	}
+
+	// The fixture has no seasons file, so the seasons come from the races
+	seasons, err := s.Seasons(ctx)
+	if err != nil {
+		t.Fatalf("Seasons: %v", err)
+	}
+	var years []string
+	for _, season := range seasons.Seasons {
+		years = append(years, season.Year)
+	}
+	if got := strings.Join(years, ","); got != "2019,2023" {
+		t.Errorf("got seasons %s, want 2019,2023", got)
+	}
+
+	// Races are sorted by season and round, whatever their order in the file
+	var rounds []string
+	for _, rd := range s.races {
+		rounds = append(rounds, rd.race.Season+"/"+rd.race.Round)
+	}
+	if got := strings.Join(rounds, ","); got != "2019/12,2019/13,2023/1,2023/2,2023/3" {
+		t.Errorf("got races %s, want 2019/12,2019/13,2023/1,2023/2,2023/3", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		skip    []string
+		replace map[string]string
+		wantErr string // empty if Load must succeed
+	}{
+		{"optional files are missing", []string{"driver_standings.csv", "constructor_standings.csv"}, nil, ""},
+		{"required file is missing", []string{"results.csv"}, nil, "opening results.csv"},
+		{
+			"race at an unknown circuit", nil,
+			map[string]string{"races.csv": "raceId,year,round,circuitId,name,date,time,url\n1,2023,1,99,Bahrain Grand Prix,2023-03-05,15:00:00,\\N\n"},
+			"races.csv, line 2: unknown circuit 99",
+		},
+		{
+			"result of an unknown race", nil,
+			map[string]string{"results.csv": "resultId,raceId,driverId,constructorId,positionOrder,statusId\n1,42,830,9,1,1\n"},
+			"results.csv, line 2: unknown race 42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(fixtureCopy(t, tt.skip, tt.replace))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Load: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Load returned error %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
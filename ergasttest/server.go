@@ -0,0 +1,235 @@
+// Package ergasttest provides a fake ergast API for tests, serving replies recorded from the real API.
+//
+// Fixtures are stored as JSON files in a directory, one per endpoint, named after the escaped endpoint
+// the same way the disk cache of the ergast package names its files. Setting the ERGASTTEST_UPSTREAM
+// environment variable to the base url of the real API, for instance "https://ergast.com/api/f1",
+// makes the servers created with NewServer record the replies of the real API into the fixtures.
+//
+// The fixtures used by the tests of this module are in the testdata directory of this package, given
+// by Fixtures. They are trimmed to what the tests need, so lists like the drivers or the calendar of a
+// season are shorter than the real ones, with totals matching the records kept.
+//
+// Replies can also be set in code, which is handy for edge cases that are hard to record,
+// like a season with no races yet or the API failing.
+package ergasttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"f1-discord-bot/ergast"
+)
+
+// UpstreamEnv is the environment variable with the base url of the API from which NewServer records fixtures
+const UpstreamEnv = "ERGASTTEST_UPSTREAM"
+
+// Server is a fake ergast API. It's safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Dir is the directory with the fixtures
+	Dir string
+	// Upstream is the base url of the API from which replies are recorded. If empty, fixtures
+	// are served from Dir, otherwise every request is forwarded to the API and its reply stored in Dir.
+	Upstream string
+
+	mu       sync.Mutex
+	replies  map[string]ergast.MRReply
+	statuses map[string]int
+	requests []string
+}
+
+// NewServer starts a fake ergast API serving the fixtures in a directory, or recording them from
+// the API given by the ERGASTTEST_UPSTREAM environment variable. The server must be closed when done.
+func NewServer(dir string) *Server {
+	s := &Server{
+		Dir:      dir,
+		Upstream: strings.TrimSuffix(os.Getenv(UpstreamEnv), "/"),
+		replies:  make(map[string]ergast.MRReply),
+		statuses: make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client for the fake API. The client doesn't cache replies, so every request reaches
+// the server. Unless recording from the real API, requests are not rate limited and failed requests
+// are retried right away.
+func (s *Server) Client() *ergast.Client {
+	client := ergast.NewClient(s.URL)
+	client.Cache = nil
+	if s.Upstream == "" {
+		client.RateLimiter = nil
+		client.RetryBaseDelay = time.Millisecond
+	}
+	return client
+}
+
+// SetReply makes the server reply to an endpoint with the given reply, instead of a fixture.
+// The endpoint must not have the limit and offset parameters, the same reply is used for all pages.
+// Missing pagination fields of the reply are filled, so it's seen as a single page.
+func (s *Server) SetReply(endpoint string, reply ergast.MRReply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies[endpoint] = reply
+}
+
+// SetStatus makes the server reply to an endpoint with an error status, like http.StatusServiceUnavailable.
+// The endpoint must not have the limit and offset parameters.
+func (s *Server) SetStatus(endpoint string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[endpoint] = status
+}
+
+// Requests returns the endpoints requested to the server so far, in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Fixtures returns the directory with the fixtures used by the tests of this module
+func Fixtures() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
+
+// FixturePath returns the path of the fixture of an endpoint in a directory
+func FixturePath(dir, endpoint string) string {
+	return filepath.Join(dir, url.PathEscape(endpoint))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.RequestURI()
+	base := withoutPagination(r.URL)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, endpoint)
+	status, hasStatus := s.statuses[base]
+	reply, hasReply := s.replies[base]
+	s.mu.Unlock()
+
+	switch {
+	case hasStatus:
+		http.Error(w, http.StatusText(status), status)
+	case hasReply:
+		fillPagination(&reply, r.URL.Query())
+		writeJSON(w, reply)
+	case s.Upstream != "":
+		s.record(w, endpoint)
+	default:
+		s.serveFixture(w, endpoint)
+	}
+}
+
+// serveFixture replies with the fixture of an endpoint, or 404 if there is none
+func (s *Server) serveFixture(w http.ResponseWriter, endpoint string) {
+	replyBytes, err := ioutil.ReadFile(FixturePath(s.Dir, endpoint))
+	if os.IsNotExist(err) {
+		http.Error(w, fmt.Sprintf("no fixture for %s", endpoint), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("reading fixture for %s: %v", endpoint, err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(replyBytes)
+}
+
+// record forwards a request to the upstream API, storing its reply as the fixture of the endpoint.
+// Only successful replies are stored, other replies are forwarded as they are.
+func (s *Server) record(w http.ResponseWriter, endpoint string) {
+	request, err := http.NewRequest(http.MethodGet, s.Upstream+endpoint, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("creating request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	request.Header.Set("User-Agent", ergast.DefaultUserAgent)
+
+	reply, err := http.DefaultClient.Do(request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("requesting upstream: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer reply.Body.Close()
+
+	replyBytes, err := ioutil.ReadAll(reply.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("reading upstream reply: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	if reply.StatusCode == http.StatusOK {
+		err = os.MkdirAll(s.Dir, 0755)
+		if err == nil {
+			err = ioutil.WriteFile(FixturePath(s.Dir, endpoint), replyBytes, 0644)
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("storing fixture for %s: %v", endpoint, err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if retryAfter := reply.Header.Get("Retry-After"); retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+	w.Header().Set("Content-Type", reply.Header.Get("Content-Type"))
+	w.WriteHeader(reply.StatusCode)
+	w.Write(replyBytes)
+}
+
+// withoutPagination returns the endpoint of a request url without the limit and offset parameters
+func withoutPagination(u *url.URL) string {
+	query := u.Query()
+	query.Del("limit")
+	query.Del("offset")
+
+	if len(query) == 0 {
+		return u.Path
+	}
+	return u.Path + "?" + query.Encode()
+}
+
+// fillPagination fills the missing pagination fields of a reply set in code.
+// A total of 0 records makes clients stop requesting pages after the first one.
+func fillPagination(reply *ergast.MRReply, query url.Values) {
+	if reply.MRData.Limit == "" {
+		reply.MRData.Limit = query.Get("limit")
+		if reply.MRData.Limit == "" {
+			reply.MRData.Limit = "30"
+		}
+	}
+	if reply.MRData.Offset == "" {
+		reply.MRData.Offset = query.Get("offset")
+		if reply.MRData.Offset == "" {
+			reply.MRData.Offset = "0"
+		}
+	}
+	if reply.MRData.Total == "" {
+		reply.MRData.Total = "0"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, reply ergast.MRReply) {
+	replyBytes, err := json.Marshal(reply)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("marshaling reply to json: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(replyBytes)
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"f1-discord-bot/commands"
+	"f1-discord-bot/ergast"
 
 	dgo "github.com/bwmarrin/discordgo"
 )
@@ -13,8 +17,19 @@ import (
 // BOT_PREFIX is the prefix for any command sent to the bot
 const BOT_PREFIX string = "!f1"
 
-// CreateMessage handles a message coming from discord
-func CreateMessage(s *dgo.Session, m *dgo.MessageCreate) {
+// CommandTimeout is the maximum time a command can take to execute
+const CommandTimeout = 30 * time.Second
+
+// CreateMessage returns the handler for messages coming from discord, answering commands with data from
+// the given data source. Commands still executing when ctx is done are canceled.
+func CreateMessage(ctx context.Context, data commands.DataSource) func(s *dgo.Session, m *dgo.MessageCreate) {
+	return func(s *dgo.Session, m *dgo.MessageCreate) {
+		handleMessage(ctx, data, s, m)
+	}
+}
+
+// handleMessage handles a message coming from discord
+func handleMessage(ctx context.Context, data commands.DataSource, s *dgo.Session, m *dgo.MessageCreate) {
 	m.Content = strings.TrimSpace(m.Content)
 	// Check if the message is intended for this bot
 	if !strings.HasPrefix(m.Content, BOT_PREFIX) {
@@ -33,40 +48,77 @@ func CreateMessage(s *dgo.Session, m *dgo.MessageCreate) {
 	// Process command and figure out the reply to send
 	c := ParseCommandArguments(m.Content)
 
-	var message string
-	var messageSend *dgo.MessageSend
-	var cmdErr error
-
-	switch c.Command {
-	case "next":
-		messageSend, cmdErr = commands.NextRace()
-	case "last":
-		message, cmdErr = commands.LastRace()
-	case "results":
-		message, cmdErr = commands.Results(c.Arguments...)
-	case "current":
-		message, cmdErr = commands.CurrentSeason()
-	case "help":
-		message = commands.Help(BOT_PREFIX)
-	default:
-		cmdErr = fmt.Errorf("command %s not recognized. Type `!f1 help` for a full list of commands available", c.Command)
-	}
+	ctx, cancel := context.WithTimeout(ctx, CommandTimeout)
+	defer cancel()
 
+	messageSend, cmdErr := RunCommand(ctx, data, BOT_PREFIX, c)
 	if cmdErr != nil {
-		message = fmt.Sprintf("Ups, seems like there was a problem executing the command: %v", cmdErr)
-	}
-
-	if message != "" {
 		messageSend = &dgo.MessageSend{
-			Content: message,
+			Content: ErrorMessage(cmdErr),
 		}
 	}
 
-	// Send the message
-	_, sendErr := s.ChannelMessageSendComplex(m.ChannelID, messageSend)
+	// Send the message, split in several if it's too long, letting the user know if discord didn't accept it
+	var sendErr error
+	for _, message := range SplitMessageSend(messageSend) {
+		_, sendErr = s.ChannelMessageSendComplex(m.ChannelID, message)
+		if sendErr != nil {
+			break
+		}
+	}
 	if sendErr != nil {
 		log.Printf("error sending message to discord: %v", sendErr)
+
+		_, err := s.ChannelMessageSend(m.ChannelID, SendErrorMessage(sendErr))
+		if err != nil {
+			log.Printf("error sending message to discord: %v", err)
+		}
 	}
 
 	log.Printf("Guild: %v | Author: %v(%v) | Command: %v | CmdErr: %v | SendErr: %v", m.GuildID, m.Author.ID, m.Author.Username, m.Content, cmdErr, sendErr)
 }
+
+// RunCommand runs a command sent to the bot with data from the given data source, returning the message to reply with.
+// The prefix is the way the user called the bot, used in the help messages.
+func RunCommand(ctx context.Context, data commands.DataSource, prefix string, c CommandArguments) (*dgo.MessageSend, error) {
+	return commands.Dispatch(ctx, data, prefix, c.Command, c.Arguments...)
+}
+
+// ErrorMessage returns the message to send to discord when a command fails
+func ErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ergast.ErrRateLimited):
+		return "The data source is busy at the moment, please try again in a minute."
+	case errors.Is(err, ergast.ErrUpstreamDown):
+		return "The data source seems to be down at the moment, please try again later."
+	case errors.Is(err, context.DeadlineExceeded):
+		return "The data source is taking too long to reply, please try again later."
+	case errors.Is(err, ergast.ErrNotFound):
+		return "Ups, no data was found for that request. Please check the arguments of the command."
+	default:
+		return fmt.Sprintf("Ups, seems like there was a problem executing the command: %v", err)
+	}
+}
+
+// SplitMessageSend splits a message with content longer than discord accepts into several messages.
+// The first message keeps everything else, like the embeds.
+func SplitMessageSend(messageSend *dgo.MessageSend) []*dgo.MessageSend {
+	contents := commands.SplitMessage(messageSend.Content, commands.MaxMessageLength)
+	if len(contents) <= 1 {
+		return []*dgo.MessageSend{messageSend}
+	}
+
+	first := *messageSend
+	first.Content = contents[0]
+	messages := []*dgo.MessageSend{&first}
+	for _, content := range contents[1:] {
+		messages = append(messages, &dgo.MessageSend{Content: content})
+	}
+	return messages
+}
+
+// SendErrorMessage returns the message to send to discord when it didn't accept the reply to a command
+func SendErrorMessage(err error) string {
+	message := fmt.Sprintf("Ups, discord didn't accept the reply to the command: %v", err)
+	return commands.TruncateText(message, commands.MaxMessageLength)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"f1-discord-bot/commands"
+	"f1-discord-bot/ergast"
+	"f1-discord-bot/ergasttest"
+
+	dgo "github.com/bwmarrin/discordgo"
+)
+
+// newServer starts a fake API serving the fixtures of the module
+func newServer(t *testing.T) *ergasttest.Server {
+	server := ergasttest.NewServer(ergasttest.Fixtures())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunCommand(t *testing.T) {
+	server := newServer(t)
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"results race 2023 bahrain", "**2023 BAHRAIN GRAND PRIX RESULTS**"},
+		{"  standings   drivers 2023 ", "**DRIVERS CHAMPIONSHIP 2023**"},
+		{"results driver max_verstappn", "Did you mean?\n\t- max_verstappen"},
+	}
+
+	for _, tt := range tests {
+		messageSend, err := RunCommand(context.Background(), server.Client(), BOT_PREFIX, ParseCommandArguments(tt.message))
+		if err != nil {
+			t.Errorf("running %q: %v", tt.message, err)
+			continue
+		}
+		if !strings.Contains(messageSend.Content, tt.want) {
+			t.Errorf("reply to %q doesn't contain %q:\n%s", tt.message, tt.want, messageSend.Content)
+		}
+	}
+}
+
+func TestRunCommandErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{"api down", http.StatusServiceUnavailable, "seems to be down"},
+		{"api rate limiting", http.StatusTooManyRequests, "is busy"},
+		{"missing data", http.StatusNotFound, "no data was found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.SetStatus("/current/next.json", tt.status)
+
+			_, err := RunCommand(context.Background(), server.Client(), BOT_PREFIX, ParseCommandArguments("next"))
+			if err == nil {
+				t.Fatalf("running the command didn't fail")
+			}
+			if message := ErrorMessage(err); !strings.Contains(message, tt.want) {
+				t.Errorf("error message %q doesn't contain %q", message, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommandOffSeason(t *testing.T) {
+	server := newServer(t)
+	server.SetReply("/current/next.json", ergast.MRReply{MRData: ergast.MRData{RaceTable: ergast.RaceTable{Season: "2024"}}})
+
+	messageSend, err := RunCommand(context.Background(), server.Client(), BOT_PREFIX, ParseCommandArguments("next"))
+	if err != nil {
+		t.Fatalf("running the command: %v", err)
+	}
+	if !strings.Contains(messageSend.Content, "no more races") {
+		t.Errorf("reply doesn't tell there are no more races:\n%s", messageSend.Content)
+	}
+}
+
+func TestSendErrorMessage(t *testing.T) {
+	message := SendErrorMessage(errors.New(strings.Repeat("a very long error from discord ", 100)))
+	if n := utf8.RuneCountInString(message); n > commands.MaxMessageLength {
+		t.Errorf("message has %d characters, more than the %d discord accepts", n, commands.MaxMessageLength)
+	}
+}
+
+func TestSplitMessageSend(t *testing.T) {
+	embeds := []*dgo.MessageEmbed{{Title: "Standings"}}
+	content := "```Header\n" + strings.Repeat("a row of a long table\n", 200) + "```"
+
+	messages := SplitMessageSend(&dgo.MessageSend{Content: content, Embeds: embeds})
+	if len(messages) < 2 {
+		t.Fatalf("message of %d characters was not split", len(content))
+	}
+	if len(messages[0].Embeds) != 1 {
+		t.Errorf("the embeds were not sent with the first message")
+	}
+
+	var rows int
+	for _, message := range messages {
+		if n := utf8.RuneCountInString(message.Content); n > commands.MaxMessageLength {
+			t.Errorf("message has %d characters, more than the %d discord accepts", n, commands.MaxMessageLength)
+		}
+		rows += strings.Count(message.Content, "a row of a long table")
+	}
+	if rows != 200 {
+		t.Errorf("%d rows were sent, want 200", rows)
+	}
+
+	// Embeds alone are sent as they are
+	if messages := SplitMessageSend(&dgo.MessageSend{Embeds: embeds}); len(messages) != 1 {
+		t.Errorf("message with only embeds was split into %d messages", len(messages))
+	}
+}
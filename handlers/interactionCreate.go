@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"f1-discord-bot/commands"
+
+	dgo "github.com/bwmarrin/discordgo"
+)
+
+// SLASH_COMMAND is the name of the slash command under which all the commands of the bot are available
+const SLASH_COMMAND string = "f1"
+
+// AutocompleteTimeout is the maximum time suggesting values for an option can take.
+// Discord discards suggestions sent after 3 seconds.
+const AutocompleteTimeout = 2500 * time.Millisecond
+
+// maxAutocompleteChoices is the maximum number of values discord accepts as suggestions
+const maxAutocompleteChoices = 25
+
+// maxChoiceNameLength is the maximum length discord accepts for the name of a suggestion
+const maxChoiceNameLength = 100
+
+// maxDescriptionLength is the maximum length discord accepts for the description of a command or option
+const maxDescriptionLength = 100
+
+// SlashCommand is the application command registered in discord. Each command of the bot is a subcommand,
+// or a group of subcommands, with the same arguments of the prefix command as options.
+var SlashCommand = &dgo.ApplicationCommand{
+	Name:        SLASH_COMMAND,
+	Description: "Formula 1 schedules, results and statistics",
+	Options:     slashOptions(commands.Commands()),
+}
+
+// slashOptions returns the options of the slash command for the given commands
+func slashOptions(cmds []*commands.Command) []*dgo.ApplicationCommandOption {
+	var options []*dgo.ApplicationCommandOption
+	for _, command := range cmds {
+		option := &dgo.ApplicationCommandOption{
+			Type:        dgo.ApplicationCommandOptionSubCommand,
+			Name:        command.Name,
+			Description: slashDescription(command.Description),
+		}
+
+		if len(command.SubCommands) > 0 {
+			option.Type = dgo.ApplicationCommandOptionSubCommandGroup
+			option.Options = slashOptions(command.SubCommands)
+		}
+
+		for _, arg := range command.Arguments {
+			option.Options = append(option.Options, &dgo.ApplicationCommandOption{
+				Type:        dgo.ApplicationCommandOptionString,
+				Name:        arg.Name,
+				Description: slashDescription(arg.Description),
+				Required:    arg.Required,
+				// Ids of drivers, circuits and constructors are hard to guess, so they are suggested as the user types
+				Autocomplete: arg.Search != nil,
+			})
+		}
+
+		options = append(options, option)
+	}
+	return options
+}
+
+// slashDescription adapts the description of a command to the rules of discord, which
+// asks for descriptions of at most 100 characters
+func slashDescription(description string) string {
+	if description == "" {
+		return description
+	}
+	return commands.TruncateText(strings.ToUpper(description[:1])+description[1:], maxDescriptionLength)
+}
+
+// RegisterSlashCommands registers the slash commands of the bot in discord, replacing the ones
+// registered before. The session must be open.
+func RegisterSlashCommands(s *dgo.Session) error {
+	_, err := s.ApplicationCommandBulkOverwrite(s.State.User.ID, "", []*dgo.ApplicationCommand{SlashCommand})
+	if err != nil {
+		return fmt.Errorf("registering slash commands: %w", err)
+	}
+	return nil
+}
+
+// InteractionCreate returns the handler for interactions coming from discord, answering slash commands
+// and suggesting values for their options with data from the given data source.
+// Commands still executing when ctx is done are canceled.
+func InteractionCreate(ctx context.Context, data commands.DataSource) func(s *dgo.Session, i *dgo.InteractionCreate) {
+	return func(s *dgo.Session, i *dgo.InteractionCreate) {
+		switch i.Type {
+		case dgo.InteractionApplicationCommand:
+			if i.ApplicationCommandData().Name == SLASH_COMMAND {
+				handleSlashCommand(ctx, data, s, i)
+			}
+		case dgo.InteractionApplicationCommandAutocomplete:
+			if i.ApplicationCommandData().Name == SLASH_COMMAND {
+				handleAutocomplete(ctx, data, s, i)
+			}
+		}
+	}
+}
+
+// handleSlashCommand handles a slash command coming from discord
+func handleSlashCommand(ctx context.Context, data commands.DataSource, s *dgo.Session, i *dgo.InteractionCreate) {
+	c := SlashCommandArguments(i.ApplicationCommandData())
+
+	// Discord only waits 3 seconds for a reply, so the reply is deferred while the command runs
+	err := s.InteractionRespond(i.Interaction, &dgo.InteractionResponse{
+		Type: dgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("error deferring reply to discord: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, CommandTimeout)
+	defer cancel()
+
+	messageSend, cmdErr := RunCommand(ctx, data, "/"+SLASH_COMMAND, c)
+	if cmdErr != nil {
+		messageSend = &dgo.MessageSend{
+			Content: ErrorMessage(cmdErr),
+		}
+	}
+
+	// Send the message, split in several if it's too long, letting the user know if discord didn't accept it.
+	// The first one replaces the deferred reply, and the rest follow it.
+	messages := SplitMessageSend(messageSend)
+	edit := &dgo.WebhookEdit{}
+	if messages[0].Content != "" {
+		edit.Content = &messages[0].Content
+	}
+	if len(messages[0].Embeds) > 0 {
+		edit.Embeds = &messages[0].Embeds
+	}
+	_, sendErr := s.InteractionResponseEdit(i.Interaction, edit)
+	if sendErr != nil {
+		log.Printf("error sending message to discord: %v", sendErr)
+
+		errorMessage := SendErrorMessage(sendErr)
+		_, err := s.InteractionResponseEdit(i.Interaction, &dgo.WebhookEdit{Content: &errorMessage})
+		if err != nil {
+			log.Printf("error sending message to discord: %v", err)
+		}
+	}
+
+	for _, message := range messages[1:] {
+		if sendErr != nil {
+			break
+		}
+
+		_, sendErr = s.FollowupMessageCreate(i.Interaction, true, &dgo.WebhookParams{Content: message.Content})
+		if sendErr != nil {
+			log.Printf("error sending message to discord: %v", sendErr)
+
+			_, err := s.FollowupMessageCreate(i.Interaction, true, &dgo.WebhookParams{Content: SendErrorMessage(sendErr)})
+			if err != nil {
+				log.Printf("error sending message to discord: %v", err)
+			}
+		}
+	}
+
+	user := i.User
+	if i.Member != nil {
+		user = i.Member.User
+	}
+	log.Printf("Guild: %v | Author: %v(%v) | Command: /%v %v %v | CmdErr: %v | SendErr: %v", i.GuildID, user.ID, user.Username, SLASH_COMMAND, c.Command, c.Arguments, cmdErr, sendErr)
+}
+
+// SlashCommandArguments converts the options of a slash command into the arguments of the equivalent prefix command.
+// Values are placed in the order the options are declared in SlashCommand, since discord doesn't keep it.
+func SlashCommandArguments(data dgo.ApplicationCommandInteractionData) CommandArguments {
+	args := optionArguments(data.Options, SlashCommand.Options)
+	if len(args) == 0 {
+		return CommandArguments{Command: "help"}
+	}
+
+	return CommandArguments{
+		Command:   args[0],
+		Arguments: args[1:],
+	}
+}
+
+// optionArguments returns the arguments given by the options of a slash command, given their declarations
+func optionArguments(options []*dgo.ApplicationCommandInteractionDataOption, declared []*dgo.ApplicationCommandOption) []string {
+	values := make(map[string]*dgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, option := range options {
+		values[option.Name] = option
+	}
+
+	var args []string
+	for _, declaration := range declared {
+		option, ok := values[declaration.Name]
+		if !ok {
+			continue
+		}
+
+		switch option.Type {
+		case dgo.ApplicationCommandOptionSubCommand, dgo.ApplicationCommandOptionSubCommandGroup:
+			args = append(args, option.Name)
+			args = append(args, optionArguments(option.Options, declaration.Options)...)
+		default:
+			args = append(args, fmt.Sprint(option.Value))
+		}
+	}
+	return args
+}
+
+// handleAutocomplete suggests values for the option of a slash command the user is typing
+func handleAutocomplete(ctx context.Context, data commands.DataSource, s *dgo.Session, i *dgo.InteractionCreate) {
+	command, option := focusedOption(commands.Commands(), i.ApplicationCommandData().Options)
+	if option == nil {
+		return
+	}
+
+	choices := []*dgo.ApplicationCommandOptionChoice{}
+	if searchFn := argumentSearch(command, option.Name); searchFn != nil {
+		ctx, cancel := context.WithTimeout(ctx, AutocompleteTimeout)
+		defer cancel()
+
+		results, err := searchFn(ctx, data, fmt.Sprint(option.Value), maxAutocompleteChoices)
+		if err != nil {
+			log.Printf("error searching values for option '%s': %v", option.Name, err)
+		}
+
+		for _, result := range results {
+			choices = append(choices, &dgo.ApplicationCommandOptionChoice{
+				Name:  commands.TruncateText(result.Name, maxChoiceNameLength),
+				Value: result.ID,
+			})
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &dgo.InteractionResponse{
+		Type: dgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &dgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		log.Printf("error sending suggestions to discord: %v", err)
+	}
+}
+
+// focusedOption returns the option the user is typing, along with the command it belongs to,
+// looking into subcommands. Returns a nil option if there is none.
+func focusedOption(cmds []*commands.Command, options []*dgo.ApplicationCommandInteractionDataOption) (*commands.Command, *dgo.ApplicationCommandInteractionDataOption) {
+	for _, option := range options {
+		if option.Type != dgo.ApplicationCommandOptionSubCommand && option.Type != dgo.ApplicationCommandOptionSubCommandGroup {
+			if option.Focused {
+				return nil, option
+			}
+			continue
+		}
+
+		command := findSubCommand(cmds, option.Name)
+		if command == nil {
+			continue
+		}
+
+		owner, focused := focusedOption(command.SubCommands, option.Options)
+		if focused != nil {
+			if owner == nil {
+				owner = command
+			}
+			return owner, focused
+		}
+	}
+	return nil, nil
+}
+
+// findSubCommand returns the command with a given name among a list of commands, or nil if there is none
+func findSubCommand(cmds []*commands.Command, name string) *commands.Command {
+	for _, command := range cmds {
+		if command.Name == name {
+			return command
+		}
+	}
+	return nil
+}
+
+// argumentSearch returns the search suggesting values for an argument of a command, or nil if there is none
+func argumentSearch(command *commands.Command, name string) commands.SearchFunc {
+	if command == nil {
+		return nil
+	}
+	for _, arg := range command.Arguments {
+		if arg.Name == name {
+			return arg.Search
+		}
+	}
+	return nil
+}
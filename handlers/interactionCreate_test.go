@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"f1-discord-bot/commands"
+
+	dgo "github.com/bwmarrin/discordgo"
+)
+
+func TestSlashCommandArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []*dgo.ApplicationCommandInteractionDataOption
+		want    CommandArguments
+	}{
+		{
+			name: "no subcommand",
+			want: CommandArguments{Command: "help"},
+		},
+		{
+			name: "options out of order",
+			options: []*dgo.ApplicationCommandInteractionDataOption{{
+				Name: "results",
+				Type: dgo.ApplicationCommandOptionSubCommandGroup,
+				Options: []*dgo.ApplicationCommandInteractionDataOption{{
+					Name: "race",
+					Type: dgo.ApplicationCommandOptionSubCommand,
+					Options: []*dgo.ApplicationCommandInteractionDataOption{
+						{Name: "round", Type: dgo.ApplicationCommandOptionString, Value: "bahrain"},
+						{Name: "season", Type: dgo.ApplicationCommandOptionString, Value: "2023"},
+					},
+				}},
+			}},
+			want: CommandArguments{Command: "results", Arguments: []string{"race", "2023", "bahrain"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SlashCommandArguments(dgo.ApplicationCommandInteractionData{Name: SLASH_COMMAND, Options: tt.options})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SlashCommandArguments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFocusedOption(t *testing.T) {
+	options := []*dgo.ApplicationCommandInteractionDataOption{{
+		Name: "results",
+		Type: dgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*dgo.ApplicationCommandInteractionDataOption{{
+			Name: "driver",
+			Type: dgo.ApplicationCommandOptionSubCommand,
+			Options: []*dgo.ApplicationCommandInteractionDataOption{
+				{Name: "driver", Type: dgo.ApplicationCommandOptionString, Value: "verst", Focused: true},
+			},
+		}},
+	}}
+
+	command, option := focusedOption(commands.Commands(), options)
+	if command == nil || command.Name != "driver" {
+		t.Fatalf("focused option belongs to %+v, want the driver results command", command)
+	}
+	if option == nil || option.Value != "verst" {
+		t.Fatalf("focused option is %+v, want the driver option", option)
+	}
+	if argumentSearch(command, option.Name) == nil {
+		t.Errorf("no search suggests values for the driver option")
+	}
+	if argumentSearch(command, "season") != nil {
+		t.Errorf("a search suggests values for an option the command doesn't have")
+	}
+
+	if _, option := focusedOption(commands.Commands(), options[0].Options[0].Options[:0]); option != nil {
+		t.Errorf("found focused option %+v among no options", option)
+	}
+}
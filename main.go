@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"f1-discord-bot/commands"
+	"f1-discord-bot/ergast"
+	"f1-discord-bot/ergastcsv"
 	"f1-discord-bot/handlers"
 
 	dgo "github.com/bwmarrin/discordgo"
@@ -15,8 +20,29 @@ import (
 // BOT_TOKEN represents the discord authentication token
 var BOT_TOKEN string
 
+// ERGAST_BASE_URL represents the base url of the ergast compatible API used to get data
+var ERGAST_BASE_URL string
+
+// ERGAST_USER_AGENT represents the user agent sent in the requests to the ergast compatible API
+var ERGAST_USER_AGENT string
+
+// CACHE_DIR represents the directory where historical data from the ergast API is stored
+var CACHE_DIR string
+
+// CSV_DIR represents the directory with the CSV dump of the ergast database, used instead of the API when set
+var CSV_DIR string
+
+// WARM_CACHE tells if the bot should only prefetch historical data into the cache directory and exit
+var WARM_CACHE bool
+
+// STATS_INTERVAL is the interval between logs of the usage stats of the bot
+const STATS_INTERVAL = time.Hour
+
 var session *dgo.Session
 
+// client is the client of the ergast API, nil when the data comes from the CSV dump
+var client *ergast.Client
+
 // Read in all configuration options from both environment variables and
 // command line arguments.
 func init() {
@@ -25,12 +51,87 @@ func init() {
 	if BOT_TOKEN == "" {
 		flag.StringVar(&BOT_TOKEN, "bot-token", "", "Discord Authentication Token")
 	}
+
+	// Ergast API
+	ERGAST_BASE_URL = os.Getenv("ERGAST_BASE_URL")
+	if ERGAST_BASE_URL == "" {
+		flag.StringVar(&ERGAST_BASE_URL, "ergast-url", ergast.DefaultBaseURL, "Base URL of the Ergast compatible API")
+	}
+
+	ERGAST_USER_AGENT = os.Getenv("ERGAST_USER_AGENT")
+	if ERGAST_USER_AGENT == "" {
+		flag.StringVar(&ERGAST_USER_AGENT, "ergast-user-agent", ergast.DefaultUserAgent, "User agent sent in the requests to the Ergast compatible API")
+	}
+
+	// Cache
+	CACHE_DIR = os.Getenv("CACHE_DIR")
+	if CACHE_DIR == "" {
+		flag.StringVar(&CACHE_DIR, "cache-dir", "", "Directory where historical data is stored. If empty, data is only cached in memory")
+	}
+	// Offline data
+	CSV_DIR = os.Getenv("CSV_DIR")
+	if CSV_DIR == "" {
+		flag.StringVar(&CSV_DIR, "csv-dir", "", "Directory with the CSV dump of the Ergast database. If set, data comes from the dump instead of the API")
+	}
+
+	flag.BoolVar(&WARM_CACHE, "warm-cache", false, "Prefetch historical data into the cache directory and exit")
+
 	flag.Parse()
 }
 
 func main() {
 	var err error
 
+	// Canceled on CTRL-C, so anything still running stops
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	var data commands.DataSource
+	if CSV_DIR != "" {
+		log.Printf("Loading CSV dump from %s", CSV_DIR)
+		data, err = ergastcsv.Load(CSV_DIR)
+		if err != nil {
+			log.Printf("error loading CSV dump: %v", err)
+			return
+		}
+	} else {
+		client = ergast.NewClient(ERGAST_BASE_URL)
+		client.UserAgent = ERGAST_USER_AGENT
+		data = client
+	}
+
+	if CACHE_DIR != "" && client != nil {
+		client.DiskCache, err = ergast.NewDiskCache(CACHE_DIR)
+		if err != nil {
+			log.Printf("error setting up cache directory: %v", err)
+			return
+		}
+	}
+
+	if WARM_CACHE {
+		if client == nil {
+			log.Print("The cache can't be warmed up when the data comes from a CSV dump.")
+			return
+		}
+		if CACHE_DIR == "" {
+			log.Print("No cache directory specified. Please specify one using the CACHE_DIR environment variable or the -cache-dir flag.")
+			return
+		}
+
+		// Warming up makes more requests than the hourly limit of the API allows,
+		// so requests wait for as long as needed instead of failing
+		client.RateLimiter.MaxWait = time.Hour
+
+		log.Printf("Warming up cache at %s", CACHE_DIR)
+		err = client.WarmCache(ctx)
+		if err != nil {
+			log.Printf("error warming up cache: %v", err)
+			return
+		}
+		log.Print("Cache is warm")
+		return
+	}
+
 	if BOT_TOKEN == "" {
 		log.Print("No bot token specified. Please specify one using the DISCORD_BOT_TOKEN environment variable or the -bot-token flag.")
 		return
@@ -50,11 +151,42 @@ func main() {
 	defer session.Close()
 
 	session.UpdateGameStatus(0, "!f1 help")
-	session.AddHandler(handlers.CreateMessage)
+	session.AddHandler(handlers.CreateMessage(ctx, data))
+	session.AddHandler(handlers.InteractionCreate(ctx, data))
+
+	err = handlers.RegisterSlashCommands(session)
+	if err != nil {
+		// The prefix commands still work without the slash commands
+		log.Printf("error registering slash commands: %v", err)
+	}
 
 	// Wait for a CTRL-C
 	log.Printf("It's lights out and away we go! Bot now running. (CTRL-C to exit)")
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	statsTicker := time.NewTicker(STATS_INTERVAL)
+	defer statsTicker.Stop()
+
+	for {
+		select {
+		case <-statsTicker.C:
+			logErgastStats()
+		case <-ctx.Done():
+			logErgastStats()
+			return
+		}
+	}
+}
+
+// logErgastStats logs the counters of the ergast client and its cache, if the API is used
+func logErgastStats() {
+	if client == nil {
+		return
+	}
+
+	stats := client.Stats()
+	log.Printf("Ergast client stats: %d requests to the API, %d coalesced requests", stats.Fetches, stats.Coalesced)
+
+	if client.Cache != nil {
+		cacheStats := client.Cache.Stats()
+		log.Printf("Ergast cache stats: %d hits, %d misses, %d entries", cacheStats.Hits, cacheStats.Misses, cacheStats.Entries)
+	}
 }